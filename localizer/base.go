@@ -0,0 +1,184 @@
+package localizer
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [<base href> 및 링크 해석 기준]
+// ==========================================
+
+// findBase: 문서에서 href가 있는 첫 번째 <base> 요소를 찾습니다. (HTML 표준상 첫 번째만 유효)
+func findBase(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode && n.Data == "base" && hasAttr(n, "href") { return n }
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if b := findBase(c); b != nil { return b }
+	}
+	return nil
+}
+
+// resolveBase: <base href>를 페이지 위치 기준으로 해석하여 pageRef.Base 값을 만듭니다.
+// 원격 모드는 절대 URL을, 로컬 모드는 rootDir 기준 경로(디렉토리이면 끝에 '/')를 반환합니다.
+// 로컬 문서의 <base>가 웹 주소를 가리키면 그 URL을 그대로 기준으로 사용합니다.
+func (m *Mirror) resolveBase(page pageRef, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") { return "", false }
+
+	if m.remote || strings.HasPrefix(href, "http") || strings.HasPrefix(href, "//") {
+		from := page.Source
+		if !m.remote { from = "https:" } // 로컬 문서: 스킴 상대 주소(//host/)만 보정
+		base, err := url.Parse(from)
+		if err != nil { return "", false }
+		ref, err := url.Parse(href)
+		if err != nil { return "", false }
+		target := base.ResolveReference(ref)
+		if target.Scheme != "http" && target.Scheme != "https" { return "", false }
+		return target.String(), true
+	}
+
+	rel := localPath(filepath.Dir(page.Source), stripQuery(href))
+	if strings.HasSuffix(href, "/") { rel += string(filepath.Separator) }
+	return rel, true
+}
+
+// linkContext: 페이지 안의 링크를 해석할 기준을 반환합니다. (downloadResource의 contextStr)
+// 원격 또는 웹 주소 기준이면 URL 자체를, 로컬이면 rootDir 기준 디렉토리를 반환합니다.
+func (m *Mirror) linkContext(page pageRef) string {
+	base := page.linkBase()
+	if strings.HasPrefix(base, "http") { return base }
+	return filepath.Dir(base)
+}
+
+// linkBase: 링크 해석 기준 문서 (<base href>가 없으면 페이지 자신)
+func (p pageRef) linkBase() string {
+	if p.Base != "" { return p.Base }
+	return p.Source
+}
+
+// localPath: 로컬 모드의 링크를 rootDir 기준 경로로 변환합니다.
+// '/'로 시작하는 링크는 사이트 루트(입력 폴더) 기준이며, 루트 밖으로 나가는 '..'은 루트에서 멈춥니다.
+func localPath(dir string, link string) string {
+	if strings.HasPrefix(link, "/") { return filepath.Join(".", filepath.FromSlash(strings.TrimPrefix(path.Clean(link), "/"))) }
+	return filepath.Join(dir, filepath.FromSlash(link))
+}
+
+// ==========================================
+// [<base> 제거 전 남은 상대 주소 보정]
+// ==========================================
+
+// URL 하나를 값으로 갖는 속성과 srcset 형식(후보 목록) 속성
+var (
+	baseURLAttrs    = map[string]bool{"href": true, "src": true, "data-src": true, "poster": true, "data": true, "action": true, "formaction": true, "cite": true, "background": true, "longdesc": true}
+	baseSrcsetAttrs = map[string]bool{"srcset": true, "data-srcset": true, "imagesrcset": true}
+)
+
+// baseRelativeValue: <base> 기준으로 해석되는 원래 값 하나 (속성 또는 <style> 텍스트)
+type baseRelativeValue struct {
+	n   *html.Node
+	key string // 속성 이름 (<style> 텍스트 노드이면 빈 문자열)
+	val string
+}
+
+// snapshotBaseRelative: 리소스 처리 전에 <base> 기준으로 해석되는 모든 URL 값을 기록합니다.
+// 처리 후에도 값이 그대로인 URL(링크 추적 범위 밖의 <a>, 다운로드 실패한 리소스 등)은 현지화되지 않은 것입니다.
+func snapshotBaseRelative(doc *html.Node) []baseRelativeValue {
+	var values []baseRelativeValue
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data != "base" {
+			for _, a := range n.Attr {
+				if baseURLAttrs[a.Key] || baseSrcsetAttrs[a.Key] || a.Key == "style" { values = append(values, baseRelativeValue{n, a.Key, a.Val}) }
+			}
+		}
+		if n.Type == html.TextNode && n.Parent != nil && n.Parent.Data == "style" { values = append(values, baseRelativeValue{n, "", n.Data}) }
+		for c := n.FirstChild; c != nil; c = c.NextSibling { walk(c) }
+	}
+	walk(doc)
+	return values
+}
+
+// absolutizeBaseRelative: <base>를 제거하기 전에, 현지화되지 않고 남은 상대 URL을 원래 뜻이 유지되도록 바꿉니다.
+// (원격/웹 주소 기준: 절대 URL, 로컬 기준: 페이지 위치 기준 상대 경로)
+// srcset과 CSS(style 속성, <style>)는 원래 값에 있던 URL과 같은 후보/참조만 바꿉니다.
+func (m *Mirror) absolutizeBaseRelative(page pageRef, values []baseRelativeValue) {
+	for _, v := range values {
+		if v.key == "" {
+			v.n.Data = m.detachCSSRefs(page, v.n.Data, v.val)
+			continue
+		}
+		for i, a := range v.n.Attr {
+			if a.Key != v.key { continue }
+			switch {
+			case a.Key == "style":
+				v.n.Attr[i].Val = m.detachCSSRefs(page, a.Val, v.val)
+			case baseSrcsetAttrs[a.Key]:
+				original := make(map[string]bool)
+				for _, c := range parseSrcset(v.val) { original[c.URL] = true }
+				cands := parseSrcset(a.Val)
+				changed := false
+				for j, c := range cands {
+					if link, ok := m.detachedLink(page, c.URL); ok && original[c.URL] { cands[j].URL, changed = link, true }
+				}
+				if changed { v.n.Attr[i].Val = formatSrcset(cands) }
+			case a.Val == v.val:
+				if link, ok := m.detachedLink(page, a.Val); ok { v.n.Attr[i].Val = link }
+			}
+		}
+	}
+}
+
+// detachCSSRefs: CSS 안의 참조 중 원래 CSS에 있던 URL 그대로 남은 참조를 detachedLink로 바꿉니다.
+func (m *Mirror) detachCSSRefs(page pageRef, css string, originalCSS string) string {
+	original := make(map[string]bool)
+	for _, ref := range scanCSSRefs(originalCSS) { original[ref.url] = true }
+	return rewriteCSS(css, scanCSSRefs(css), func(ref cssRef) (string, bool) {
+		if !original[ref.url] { return "", false }
+		return m.detachedLink(page, ref.url)
+	})
+}
+
+// detachedLink: <base> 기준 링크를 <base> 없이도 같은 대상을 가리키는 값으로 바꿉니다.
+// 같은 문서 안의 #fragment, data: 등과 이미 절대 주소인 링크는 바꾸지 않습니다. (ok=false)
+func (m *Mirror) detachedLink(page pageRef, link string) (string, bool) {
+	link = strings.TrimSpace(link)
+	if page.Base == "" || shouldIgnoreLink(link) { return "", false }
+	ref, err := url.Parse(link)
+	if err != nil { return "", false }
+
+	if strings.HasPrefix(page.Base, "http") {
+		base, err := url.Parse(page.Base)
+		if err != nil { return "", false }
+		resolved := base.ResolveReference(ref).String()
+		return resolved, resolved != link
+	}
+
+	// 로컬 기준: 스킴/호스트가 있거나 사이트 루트 기준('/')이면 <base>와 관계없음
+	if ref.Scheme != "" || ref.Host != "" || ref.Path == "" || strings.HasPrefix(ref.Path, "/") { return "", false }
+	suffix := ""
+	if i := strings.IndexAny(link, "?#"); i != -1 { suffix = link[i:] }
+	target := localPath(filepath.Dir(page.Base), stripQuery(link))
+	rel, err := filepath.Rel(filepath.Dir(page.Source), target)
+	if err != nil { return "", false }
+	rel = filepath.ToSlash(rel)
+	if strings.HasSuffix(ref.Path, "/") { rel += "/" }
+	return rel + suffix, rel+suffix != link
+}
+
+// detachBase: 모든 링크를 저장 위치 기준으로 바꾼 뒤 <base>의 href를 제거합니다.
+// 남겨 두면 로컬 상대 경로가 원래 기준 주소로 해석되므로, target 속성이 없으면 요소 자체를 제거합니다.
+func detachBase(n *html.Node) {
+	if hasAttr(n, "target") {
+		var keep []html.Attribute
+		for _, a := range n.Attr {
+			if a.Key != "href" { keep = append(keep, a) }
+		}
+		n.Attr = keep
+		return
+	}
+	if n.Parent != nil { n.Parent.RemoveChild(n) }
+}
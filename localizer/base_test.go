@@ -0,0 +1,81 @@
+package localizer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestDetachedLink(t *testing.T) {
+	tests := []struct {
+		remote bool
+		page   pageRef
+		link   string
+		want   string // 빈 문자열이면 바꾸지 않음
+	}{
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "img/a.png", "https://cdn.com/x/img/a.png"},
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "/abs.html?q=1#f", "https://cdn.com/abs.html?q=1#f"},
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "//other.com/a", "https://other.com/a"},
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "https://b.com/", ""},
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "#top", ""},
+		{true, pageRef{Source: "https://a.com/p/index.html", Base: "https://cdn.com/x/"}, "mailto:a@b.c", ""},
+		{true, pageRef{Source: "https://a.com/p/index.html"}, "img/a.png", ""}, // <base> 없음
+		// 로컬 문서의 로컬 <base>: 페이지 위치 기준 경로
+		{false, pageRef{Source: "index.html", Base: "sub/"}, "img/a.png", "sub/img/a.png"},
+		{false, pageRef{Source: "index.html", Base: "sub/"}, "page.html?q=1#f", "sub/page.html?q=1#f"},
+		{false, pageRef{Source: "index.html", Base: "sub/"}, "dir/", "sub/dir/"},
+		{false, pageRef{Source: "docs/index.html", Base: "other/x.html"}, "../a.png", ""}, // 결과가 같으면 그대로
+		{false, pageRef{Source: "docs/index.html", Base: "other/x.html"}, "a.png", "../other/a.png"},
+		{false, pageRef{Source: "index.html", Base: "sub/"}, "/root.html", ""},
+		{false, pageRef{Source: "index.html", Base: "sub/"}, "https://x.com/a", ""},
+		// 로컬 문서의 웹 주소 <base>
+		{false, pageRef{Source: "index.html", Base: "https://cdn.com/x/"}, "a.png", "https://cdn.com/x/a.png"},
+	}
+	for _, tt := range tests {
+		m := &Mirror{remote: tt.remote}
+		got, ok := m.detachedLink(tt.page, tt.link)
+		if !ok { got = "" }
+		if got != tt.want {
+			t.Errorf("detachedLink(%+v, %q) = %q, want %q", tt.page, tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestAbsolutizeBaseRelative(t *testing.T) {
+	src := `<html><head><base href="https://cdn.com/x/"><style>.a{background:url(a.png)} .b{background:url(b.png)}</style></head><body>` +
+		`<a href="page.html">p</a><a href="#top">t</a><img src="a.png" srcset="a.png 1x, b.png 2x" style="background:url(b.png)"><form action="post"></form></body></html>`
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil { t.Fatal(err) }
+	page := pageRef{Source: "https://a.com/index.html", Base: "https://cdn.com/x/"}
+	values := snapshotBaseRelative(doc)
+
+	// a.png만 현지화된 상태를 흉내 냄 (b.png, <a>, <form>은 그대로 남음)
+	var localize func(*html.Node)
+	localize = func(n *html.Node) {
+		if n.Type == html.TextNode && n.Parent.Data == "style" { n.Data = strings.Replace(n.Data, "url(a.png)", "url('assets/a.png')", 1) }
+		for i, a := range n.Attr {
+			switch a.Key {
+			case "src":
+				n.Attr[i].Val = "assets/a.png"
+			case "srcset":
+				n.Attr[i].Val = "assets/a.png 1x, b.png 2x"
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling { localize(c) }
+	}
+	localize(doc)
+
+	m := &Mirror{remote: true}
+	m.absolutizeBaseRelative(page, values)
+	detachBase(findBase(doc))
+
+	var buf bytes.Buffer
+	html.Render(&buf, doc)
+	want := `<html><head><style>.a{background:url('assets/a.png')} .b{background:url('https://cdn.com/x/b.png')}</style></head><body>` +
+		`<a href="https://cdn.com/x/page.html">p</a><a href="#top">t</a><img src="assets/a.png" srcset="assets/a.png 1x, https://cdn.com/x/b.png 2x" style="background:url(&#39;https://cdn.com/x/b.png&#39;)"/><form action="https://cdn.com/x/post"></form></body></html>`
+	if buf.String() != want {
+		t.Errorf("absolutizeBaseRelative:\n got %s\nwant %s", buf.String(), want)
+	}
+}
@@ -0,0 +1,100 @@
+package localizer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [공유 브라우저 (Browser Pool)]
+// ==========================================
+
+// DefaultMaxTabs: Options.MaxTabs 미지정 시 동시에 열 수 있는 브라우저 탭 수
+const DefaultMaxTabs = 2
+
+// browserPool: 한 번의 실행 동안 하나의 Chrome 프로세스를 공유하고, 페이지마다 탭을 엽니다.
+// Chrome은 처음 탭이 필요할 때 실행되며 close 호출 시 종료됩니다.
+type browserPool struct {
+	tabs      chan struct{}   // 동시에 열린 탭 수 제한
+	userAgent string          // 브라우저 User-Agent (HTTP 요청과 같은 값)
+	setup     chromedp.Action // 브라우저 실행 직후 한 번 수행할 작업 (초기 쿠키 설정 등, nil 가능)
+
+	mu            sync.Mutex
+	started       bool
+	closed        bool
+	browserCtx    context.Context
+	cancelBrowser context.CancelFunc
+	cancelAlloc   context.CancelFunc
+}
+
+func newBrowserPool(maxTabs int, userAgent string, setup chromedp.Action) *browserPool {
+	if maxTabs <= 0 { maxTabs = DefaultMaxTabs }
+	return &browserPool{tabs: make(chan struct{}, maxTabs), userAgent: userAgent, setup: setup}
+}
+
+// start: Chrome 프로세스를 실행합니다. 이미 실행 중이면 아무 것도 하지 않습니다.
+// 브라우저 수명은 개별 요청 컨텍스트가 아니라 close 호출로 관리됩니다.
+func (b *browserPool) start() (context.Context, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed { return nil, context.Canceled }
+	if b.started { return b.browserCtx, nil }
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.UserAgent(b.userAgent),
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+
+	// 첫 Run에서 브라우저 프로세스가 실행됨 (탭들이 공유하는 쿠키 등은 이때 설정)
+	var actions []chromedp.Action
+	if b.setup != nil { actions = append(actions, b.setup) }
+	if err := chromedp.Run(browserCtx, actions...); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		return nil, err
+	}
+	b.started = true
+	b.browserCtx, b.cancelBrowser, b.cancelAlloc = browserCtx, cancelBrowser, cancelAlloc
+	return browserCtx, nil
+}
+
+// newTab: 공유 브라우저에 새 탭을 엽니다. 탭 수 제한에 걸리면 빈 자리가 날 때까지 기다립니다.
+// 반환된 탭 컨텍스트는 ctx가 취소되면 함께 닫히며, release를 호출하면 탭이 닫히고 자리가 반환됩니다.
+func (b *browserPool) newTab(ctx context.Context) (tabCtx context.Context, release func(), err error) {
+	select {
+	case b.tabs <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	browserCtx, err := b.start()
+	if err != nil {
+		<-b.tabs
+		return nil, nil, err
+	}
+
+	tabCtx, cancelTab := chromedp.NewContext(browserCtx)
+	stop := context.AfterFunc(ctx, cancelTab)
+	release = func() {
+		stop()
+		cancelTab()
+		<-b.tabs
+	}
+	return tabCtx, release, nil
+}
+
+// close: 브라우저를 종료합니다. 여러 번 호출해도 안전합니다.
+func (b *browserPool) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	if !b.started { return }
+	b.started = false
+	b.cancelBrowser()
+	b.cancelAlloc()
+}
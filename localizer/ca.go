@@ -0,0 +1,124 @@
+package localizer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// [기록 프록시용 로컬 인증 기관 (HTTPS 가로채기)]
+// ==========================================
+
+const (
+	caCertFile = "localizer-ca.pem"     // 브라우저/OS에 신뢰 등록할 CA 인증서
+	caKeyFile  = "localizer-ca-key.pem" // CA 개인 키 (외부에 공유하지 않음)
+)
+
+// localCA: HTTPS 사이트마다 서버 인증서를 즉석에서 발급하는 로컬 인증 기관입니다.
+type localCA struct {
+	certPath string
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	leafKey  *ecdsa.PrivateKey // 모든 사이트 인증서가 공유하는 키 (발급 속도 향상)
+
+	mu    sync.Mutex
+	leafs map[string]*tls.Certificate // 호스트 -> 발급한 인증서
+}
+
+// loadLocalCA: dir의 CA 인증서와 키를 읽고, 없으면 새로 만들어 저장합니다.
+// 한 번 신뢰 등록한 CA를 계속 쓸 수 있도록 실행할 때마다 다시 만들지 않습니다.
+func loadLocalCA(dir string) (*localCA, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+
+	if !fileExists(certPath) || !fileExists(keyPath) {
+		if err := createLocalCA(certPath, keyPath); err != nil { return nil, fmt.Errorf("CA 인증서 생성 실패: %w", err) }
+	}
+
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil { return nil, err }
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil { return nil, err }
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil { return nil, fmt.Errorf("CA 인증서를 읽을 수 없습니다 (%s): %w", certPath, err) }
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil { return nil, err }
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok { return nil, fmt.Errorf("지원하지 않는 CA 키 형식입니다 (%s)", keyPath) }
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil { return nil, err }
+	return &localCA{certPath: certPath, cert: cert, key: key, leafKey: leafKey, leafs: make(map[string]*tls.Certificate)}, nil
+}
+
+// createLocalCA: 10년 유효한 자체 서명 CA를 만듭니다. (키 파일은 소유자만 읽을 수 있음)
+func createLocalCA(certPath string, keyPath string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil { return err }
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "localizer Recording CA", Organization: []string{"localizer"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil { return err }
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil { return err }
+
+	if err := os.MkdirAll(filepath.Dir(certPath), 0755); err != nil { return err }
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil { return err }
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// certFor: 호스트용 서버 인증서를 발급합니다. (같은 호스트는 재사용)
+// 브라우저의 최대 유효 기간(398일) 제한을 넘지 않도록 1년짜리로 발급합니다.
+func (ca *localCA) certFor(host string) (*tls.Certificate, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if cert, ok := ca.leafs[host]; ok { return cert, nil }
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: host, Organization: []string{"localizer"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	} else {
+		tmpl.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &ca.leafKey.PublicKey, ca.key)
+	if err != nil { return nil, err }
+
+	cert := &tls.Certificate{Certificate: [][]byte{der, ca.cert.Raw}, PrivateKey: ca.leafKey}
+	ca.leafs[host] = cert
+	return cert, nil
+}
+
+// randomSerial: 인증서 일련번호 (128비트 난수)
+func randomSerial() *big.Int {
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return serial
+}
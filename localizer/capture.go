@@ -0,0 +1,172 @@
+package localizer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [브라우저 네트워크 응답 캡처]
+// ==========================================
+
+// capturedResponse: 렌더링 중 브라우저가 실제로 받은 응답 하나입니다.
+type capturedResponse struct {
+	URL      string               // 최종 응답 URL
+	Page     string               // 이 응답을 요청한 페이지 URL
+	Status   int                  // HTTP 상태 코드
+	MimeType string               // 응답 MIME 타입
+	Type     network.ResourceType // 리소스 종류 (Image, Script, XHR 등)
+	Body     []byte
+	urls     []string // 리다이렉트를 포함해 이 응답에 도달한 모든 요청 URL
+
+	// 아카이브(WARC) 기록용 요청/응답 정보
+	Method          string
+	StatusText      string
+	RequestHeaders  network.Headers
+	ResponseHeaders network.Headers
+}
+
+// responseRecorder: CDP Network 이벤트로 응답을 기록하고, 렌더링 종료 시 본문을 가져옵니다.
+type responseRecorder struct {
+	page      string
+	mu        sync.Mutex
+	requests  map[network.RequestID]*capturedResponse
+	finished  []network.RequestID
+	redirects []*capturedResponse // 리다이렉트 중간 응답 (본문 없음, 아카이브 기록용)
+}
+
+func newResponseRecorder(page string) *responseRecorder {
+	return &responseRecorder{page: page, requests: make(map[network.RequestID]*capturedResponse)}
+}
+
+// handle: chromedp.ListenTarget 에 등록되는 이벤트 처리 함수
+func (r *responseRecorder) handle(ev any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		rec, ok := r.requests[ev.RequestID]
+		if !ok {
+			rec = &capturedResponse{Page: r.page}
+			r.requests[ev.RequestID] = rec
+		}
+		if ok && ev.RedirectResponse != nil {
+			r.redirects = append(r.redirects, &capturedResponse{
+				URL:             ev.RedirectResponse.URL,
+				Page:            r.page,
+				Status:          int(ev.RedirectResponse.Status),
+				StatusText:      ev.RedirectResponse.StatusText,
+				MimeType:        ev.RedirectResponse.MimeType,
+				Type:            rec.Type,
+				Method:          rec.Method,
+				RequestHeaders:  rec.RequestHeaders,
+				ResponseHeaders: ev.RedirectResponse.Headers,
+			})
+		}
+		rec.urls = append(rec.urls, ev.Request.URL)
+		rec.Type = ev.Type
+		rec.Method = ev.Request.Method
+		rec.RequestHeaders = ev.Request.Headers
+	case *network.EventResponseReceived:
+		if rec, ok := r.requests[ev.RequestID]; ok {
+			rec.URL = ev.Response.URL
+			rec.Status = int(ev.Response.Status)
+			rec.MimeType = ev.Response.MimeType
+			rec.Type = ev.Type
+			rec.StatusText = ev.Response.StatusText
+			rec.ResponseHeaders = ev.Response.Headers
+		}
+	case *network.EventLoadingFinished:
+		r.finished = append(r.finished, ev.RequestID)
+	}
+}
+
+// collect: 정상 완료된 응답의 본문을 가져옵니다. 탭이 닫히기 전에 호출해야 합니다.
+// all이 true이면 (아카이브 기록용) 페이지 문서, 오류 응답, 리다이렉트 중간 응답을 포함한 모든 http(s) 응답을 가져옵니다.
+func (r *responseRecorder) collect(ctx context.Context, all bool) []*capturedResponse {
+	r.mu.Lock()
+	var targets []network.RequestID
+	for _, id := range r.finished {
+		rec := r.requests[id]
+		if rec == nil { continue }
+		if isCapturable(rec) || (all && rec.Status > 0 && isHTTPURL(rec.URL)) { targets = append(targets, id) }
+	}
+	var responses []*capturedResponse
+	if all { responses = append(responses, r.redirects...) }
+	r.mu.Unlock()
+
+	for _, id := range targets {
+		var body []byte
+		err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			body, err = network.GetResponseBody(id).Do(ctx)
+			return err
+		}))
+		if err != nil { continue } // 본문이 이미 해제된 경우 등은 HTTP 다운로드로 대체
+		r.mu.Lock()
+		rec := r.requests[id]
+		rec.Body = body
+		r.mu.Unlock()
+		responses = append(responses, rec)
+	}
+	return responses
+}
+
+// isCapturable: 미러에 저장할 응답인지 확인합니다. (페이지 문서, 소켓, 핑 등 제외)
+func isCapturable(rec *capturedResponse) bool {
+	if rec.Status < 200 || rec.Status >= 300 || rec.Status == 204 { return false }
+	if rec.Status == 206 { return false } // Range 요청의 부분 응답(주로 video/audio)은 전체 파일이 아니므로 HTTP로 다시 받음
+	if !isHTTPURL(rec.URL) { return false }
+	switch rec.Type {
+	case network.ResourceTypeStylesheet, network.ResourceTypeImage, network.ResourceTypeMedia,
+		network.ResourceTypeFont, network.ResourceTypeScript, network.ResourceTypeTextTrack,
+		network.ResourceTypeXHR, network.ResourceTypeFetch, network.ResourceTypeManifest:
+		return true
+	}
+	return false
+}
+
+// storeCaptured: 캡처한 응답을 저장소에 등록합니다. 이후 같은 URL은 HTTP로 다시 받지 않습니다.
+func (m *Mirror) storeCaptured(responses []*capturedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, rec := range responses {
+		if !isCapturable(rec) { continue }
+		m.captured[stripFragment(rec.URL)] = rec
+		for _, u := range rec.urls { m.captured[stripFragment(u)] = rec }
+	}
+}
+
+// capturedFor: URL에 해당하는 캡처된 응답을 찾습니다.
+func (m *Mirror) capturedFor(targetURL string) (*capturedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.captured[stripFragment(targetURL)]
+	return rec, ok
+}
+
+// capturedForPage: 페이지 렌더링 중 로드된 응답 URL 목록 (HTML에서 참조되지 않은 동적 리소스 저장용)
+func (m *Mirror) capturedForPage(pageURL string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var urls []string
+	for key, rec := range m.captured {
+		if rec.Page == pageURL && key == stripFragment(rec.URL) { urls = append(urls, rec.URL) }
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func isHTTPURL(u string) bool {
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")
+}
+
+func stripFragment(u string) string {
+	if i := strings.IndexByte(u, '#'); i != -1 { return u[:i] }
+	return u
+}
@@ -0,0 +1,263 @@
+package localizer
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [페이지 크롤링 (<a href> 추적) 및 범위 규칙]
+// ==========================================
+
+// Scope: <a href> 링크를 따라갈 때 허용되는 범위입니다.
+type Scope string
+
+const (
+	ScopeHost   Scope = "host"   // 시작 URL과 같은 호스트 (기본값)
+	ScopePrefix Scope = "prefix" // 같은 호스트이면서 시작 URL 경로 하위
+	ScopeHosts  Scope = "hosts"  // 시작 호스트 + Options.AllowedHosts 에 나열된 호스트(하위 도메인 포함)
+)
+
+// ParseScope: 문자열을 Scope로 변환합니다. 빈 문자열은 ScopeHost로 취급합니다.
+func ParseScope(s string) (Scope, bool) {
+	switch Scope(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ScopeHost:
+		return ScopeHost, true
+	case ScopePrefix:
+		return ScopePrefix, true
+	case ScopeHosts:
+		return ScopeHosts, true
+	}
+	return "", false
+}
+
+// pageRef: 처리할 HTML 페이지 하나를 가리킵니다.
+type pageRef struct {
+	Source string // 원격: 절대 URL, 로컬: rootDir 기준 상대 경로
+	OutRel string // 출력 폴더 기준 저장 경로
+	Depth  int    // 시작 페이지로부터 따라온 <a href> 링크 수
+	Base   string // <base href>를 해석한 링크 기준 (없으면 Source 기준, processHTMLFile에서 설정)
+}
+
+// startPage: 시작 파일을 가리키는 pageRef를 생성합니다.
+func (m *Mirror) startPage() pageRef {
+	if !m.remote { return pageRef{Source: m.startFile, OutRel: m.startFile} }
+	u, _ := url.Parse(m.rootDir)
+	rel, _ := url.Parse(m.startFile)
+	target := u.ResolveReference(rel)
+	return pageRef{Source: target.String(), OutRel: m.pageOutPath(target)}
+}
+
+// resolvePage: 현재 페이지 기준의 링크를 범위 규칙에 맞는 페이지로 변환합니다.
+// 페이지가 아니거나(이미지, PDF 등) 범위 밖이면 ok=false를 반환합니다.
+func (m *Mirror) resolvePage(from pageRef, link string) (page pageRef, fragment string, ok bool) {
+	link = strings.TrimSpace(link)
+	if shouldIgnoreLink(link) { return pageRef{}, "", false }
+	if i := strings.IndexByte(link, '#'); i != -1 { link, fragment = link[:i], link[i:] }
+
+	if m.remote {
+		base, err := url.Parse(from.linkBase())
+		if err != nil { return pageRef{}, "", false }
+		ref, err := url.Parse(link)
+		if err != nil { return pageRef{}, "", false }
+		target := base.ResolveReference(ref)
+		target.Fragment = ""
+		if target.Scheme != "http" && target.Scheme != "https" { return pageRef{}, "", false }
+		if !isPageLink(target.Path) || !m.inScope(target) { return pageRef{}, "", false }
+		return pageRef{Source: target.String(), OutRel: m.pageOutPath(target), Depth: from.Depth}, fragment, true
+	}
+
+	// 로컬 모드: 루트 폴더 안의 실제 HTML 파일만 추적
+	// (<base>가 웹 주소를 가리키면 모든 상대 링크가 원격 페이지이므로 제외)
+	if strings.HasPrefix(link, "http") || strings.HasPrefix(link, "//") || strings.HasPrefix(from.linkBase(), "http") { return pageRef{}, "", false }
+	rel := localPath(filepath.Dir(from.linkBase()), stripQuery(link))
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) { return pageRef{}, "", false }
+	if info, err := os.Stat(filepath.Join(m.rootDir, rel)); err == nil && info.IsDir() {
+		rel = filepath.Join(rel, "index.html")
+	}
+	if ext := strings.ToLower(filepath.Ext(rel)); ext != ".html" && ext != ".htm" { return pageRef{}, "", false }
+	if !fileExists(filepath.Join(m.rootDir, rel)) { return pageRef{}, "", false }
+	return pageRef{Source: rel, OutRel: rel, Depth: from.Depth}, fragment, true
+}
+
+// pageOutPath: 원격 페이지 URL의 저장 경로를 계산합니다.
+// 시작 URL 하위 페이지는 같은 상대 구조로, 그 밖의 페이지는 hosts/<호스트>/ 아래에 저장합니다.
+// 확장자가 없는 경로(/about)는 about.html, 디렉토리(/docs/)는 docs/index.html 이 됩니다.
+func (m *Mirror) pageOutPath(u *url.URL) string {
+	p := u.Path
+	if p == "" || strings.HasSuffix(p, "/") { p += "index.html" }
+	if ext := strings.ToLower(path.Ext(p)); ext != ".html" && ext != ".htm" { p += ".html" }
+	if u.RawQuery != "" { p = addNameSuffix(p, shortHash([]byte(u.RawQuery))) }
+
+	var rel string
+	root, _ := url.Parse(m.rootDir)
+	if root != nil && u.Host == root.Host && strings.HasPrefix(p, root.Path) {
+		rel = strings.TrimPrefix(p, root.Path)
+	} else {
+		rel = path.Join("hosts", u.Host, p)
+	}
+
+	var segments []string
+	for _, seg := range strings.Split(rel, "/") {
+		if seg == "" || seg == "." || seg == ".." { continue }
+		segments = append(segments, sanitizeName(seg))
+	}
+	return filepath.Join(segments...)
+}
+
+// inScope: 원격 페이지 URL이 크롤링 범위 안에 있는지 확인합니다.
+func (m *Mirror) inScope(u *url.URL) bool {
+	root, err := url.Parse(m.rootDir)
+	if err != nil { return false }
+	sameHost := strings.EqualFold(u.Host, root.Host)
+	switch m.opts.Scope {
+	case ScopePrefix:
+		return sameHost && strings.HasPrefix(u.Path, root.Path)
+	case ScopeHosts:
+		if sameHost { return true }
+		host := strings.ToLower(u.Hostname())
+		for _, allowed := range m.opts.AllowedHosts {
+			allowed = strings.ToLower(strings.TrimSpace(allowed))
+			if allowed != "" && (host == allowed || strings.HasSuffix(host, "."+allowed)) { return true }
+		}
+		return false
+	}
+	return sameHost
+}
+
+// isPageLink: 경로가 HTML 페이지로 보이는지 확인합니다. (이미지, PDF 등의 다운로드 링크 제외)
+func isPageLink(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case "", ".html", ".htm", ".shtml", ".xhtml", ".php", ".asp", ".aspx", ".jsp":
+		return true
+	}
+	return false
+}
+
+// schedulePage: 페이지를 처리 대상으로 등록합니다. 이미 등록된 페이지이면 false를 반환합니다.
+func (m *Mirror) schedulePage(page pageRef) bool {
+	key := filepath.ToSlash(page.OutRel)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.visitedHTMLs[key] { return false }
+	m.visitedHTMLs[key] = true
+	return true
+}
+
+// isScheduled: 페이지가 이미 처리 대상으로 등록되었는지 확인합니다.
+func (m *Mirror) isScheduled(page pageRef) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.visitedHTMLs[filepath.ToSlash(page.OutRel)]
+}
+
+// enqueuePage: <a href>로 발견한 페이지를 깊이 제한 안에서 크롤링 대기열에 추가합니다.
+func (m *Mirror) enqueuePage(page pageRef) {
+	if page.Depth > m.opts.Depth { return }
+	if !m.schedulePage(page) { return }
+	m.mu.Lock()
+	m.pageQueue = append(m.pageQueue, page)
+	m.mu.Unlock()
+}
+
+// drainPages: 크롤링 대기열의 페이지를 모두 꺼냅니다. (한 번에 한 깊이씩 처리되는 너비 우선 순회)
+func (m *Mirror) drainPages() []pageRef {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pages := m.pageQueue
+	m.pageQueue = nil
+	return pages
+}
+
+// pageLink: from 페이지에서 to 페이지를 가리키는 상대 경로를 계산합니다.
+func pageLink(from pageRef, to pageRef, fragment string) (string, error) {
+	rel, err := filepath.Rel(filepath.Dir(from.OutRel), to.OutRel)
+	if err != nil { return "", fmt.Errorf("상대 경로 계산 실패: %w", err) }
+	return filepath.ToSlash(rel) + fragment, nil
+}
+
+// ==========================================
+// [저장되지 않은 페이지로의 링크 복원]
+// ==========================================
+
+// pageLinkRef: 저장된 페이지 안에서 다른 페이지를 가리키도록 바꾼 링크 하나
+type pageLinkRef struct {
+	Target   string // 대상 페이지 저장 경로 (출력 폴더 기준)
+	Local    string // 바꾼 값 (로컬 상대 경로 + #fragment)
+	Original string // 대상이 저장되지 않았을 때 되돌릴 값 (원격: 절대 URL, 로컬: 원래 값)
+}
+
+// newPageLinkRef: 링크 복원 정보를 만듭니다. 되돌릴 값은 <base> 제거 후에도 유효하도록
+// 원격 페이지는 절대 URL로, 로컬 페이지는 from 페이지 위치 기준 경로로 만듭니다.
+func (m *Mirror) newPageLinkRef(from pageRef, target pageRef, fragment string, local string, original string) pageLinkRef {
+	if m.remote {
+		original = target.Source + fragment
+	} else if link, ok := m.detachedLink(from, original); ok {
+		original = link
+	}
+	return pageLinkRef{Target: target.OutRel, Local: local, Original: original}
+}
+
+// addPageLinks: 저장을 마친 페이지의 로컬 페이지 링크를 기록합니다.
+func (m *Mirror) addPageLinks(page pageRef, links []pageLinkRef) {
+	if len(links) == 0 { return }
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pageLinks[page.OutRel] = links
+}
+
+// restoreMissingPageLinks: 대상 페이지가 출력 폴더에 없는(처리 실패, 시간 초과로 중단) 링크를
+// 원래 주소로 되돌려 미러에 깨진 로컬 링크가 남지 않게 합니다.
+func (m *Mirror) restoreMissingPageLinks() {
+	if m.opts.Format == FormatArchive { return }
+	m.mu.Lock()
+	pages := m.pageLinks
+	m.pageLinks = make(map[string][]pageLinkRef)
+	m.mu.Unlock()
+
+	for outRel, links := range pages {
+		restore := make(map[string]string)
+		for _, l := range links {
+			if !fileExists(filepath.Join(m.opts.OutputDir, l.Target)) { restore[l.Local] = l.Original }
+		}
+		if len(restore) == 0 { continue }
+		if err := rewritePageLinks(filepath.Join(m.opts.OutputDir, outRel), restore); err != nil {
+			m.logf(" ⚠️  링크 복원 실패 (%s): %v\n", outRel, err)
+			continue
+		}
+		m.logf(" ↩️  저장되지 않은 페이지 링크 %d개를 원래 주소로 복원: %s\n", len(restore), filepath.ToSlash(outRel))
+	}
+}
+
+// rewritePageLinks: 저장된 HTML에서 a[href], iframe[src] 값이 restore의 키와 같으면 그 값으로 바꿉니다.
+func rewritePageLinks(file string, restore map[string]string) error {
+	data, err := os.ReadFile(file)
+	if err != nil { return err }
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil { return err }
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "a" || n.Data == "iframe") {
+			key := "href"
+			if n.Data == "iframe" { key = "src" }
+			for i, a := range n.Attr {
+				if a.Key != key { continue }
+				if original, ok := restore[a.Val]; ok { n.Attr[i].Val = original }
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling { walk(c) }
+	}
+	walk(doc)
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil { return err }
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,29 @@
+package localizer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreMissingPageLinks(t *testing.T) {
+	dir := t.TempDir()
+	page := `<html><head></head><body><a href="ok.html#x">ok</a><a href="sub/gone.html">gone</a><iframe src="sub/gone.html"></iframe><a href="other.html">other</a></body></html>`
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(page), 0644); err != nil { t.Fatal(err) }
+	if err := os.WriteFile(filepath.Join(dir, "ok.html"), []byte("ok"), 0644); err != nil { t.Fatal(err) }
+
+	m := &Mirror{opts: Options{OutputDir: dir}, log: io.Discard, remote: true, pageLinks: make(map[string][]pageLinkRef)}
+	m.addPageLinks(pageRef{OutRel: "index.html"}, []pageLinkRef{
+		m.newPageLinkRef(pageRef{}, pageRef{Source: "https://example.com/ok", OutRel: "ok.html"}, "#x", "ok.html#x", "ok#x"),
+		m.newPageLinkRef(pageRef{}, pageRef{Source: "https://example.com/sub/gone", OutRel: filepath.Join("sub", "gone.html")}, "", "sub/gone.html", "sub/gone"),
+	})
+	m.restoreMissingPageLinks()
+
+	got, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil { t.Fatal(err) }
+	want := `<html><head></head><body><a href="ok.html#x">ok</a><a href="https://example.com/sub/gone">gone</a><iframe src="https://example.com/sub/gone"></iframe><a href="other.html">other</a></body></html>`
+	if string(got) != want {
+		t.Errorf("restoreMissingPageLinks:\n got %s\nwant %s", got, want)
+	}
+}
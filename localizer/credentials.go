@@ -0,0 +1,254 @@
+package localizer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [요청 헤더, 쿠키, 인증 (HTTP 클라이언트와 Chrome 공통)]
+// ==========================================
+
+// defaultUserAgent: HTTP 요청과 Chrome이 함께 사용하는 기본 User-Agent (Options.Headers의 User-Agent로 변경)
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36"
+
+// ParseHeader: "이름: 값" 형식의 요청 헤더를 나눕니다.
+func ParseHeader(s string) (name string, value string, err error) {
+	name, value, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") { return "", "", fmt.Errorf("잘못된 헤더 형식입니다 (이름: 값): %s", s) }
+	return http.CanonicalHeaderKey(name), strings.TrimSpace(value), nil
+}
+
+// ParseCookies: "이름=값; 이름2=값2" 형식의 쿠키 목록을 읽습니다. (Domain이 비어 있으므로 시작 URL의 호스트에 설정됨)
+func ParseCookies(s string) ([]*http.Cookie, error) {
+	cookies, err := http.ParseCookie(s)
+	if err != nil { return nil, fmt.Errorf("잘못된 쿠키 형식입니다 (이름=값): %s", s) }
+	return cookies, nil
+}
+
+// LoadCookiesFile: Netscape cookies.txt 파일(curl -c, 브라우저 확장 프로그램 형식)의 쿠키를 읽습니다.
+// 하위 도메인에도 보내는 쿠키는 Domain이 "."으로 시작하며, 이미 만료된 쿠키는 제외합니다.
+func LoadCookiesFile(path string) ([]*http.Cookie, error) {
+	f, err := os.Open(path)
+	if err != nil { return nil, err }
+	defer f.Close()
+
+	var cookies []*http.Cookie
+	now := time.Now()
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line, httpOnly = rest, true
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") { continue }
+
+		// 도메인, 하위 도메인 포함, 경로, HTTPS 전용, 만료 시각(유닉스 초, 0이면 세션), 이름, 값
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 { fields = append(fields, "") }
+		if len(fields) != 7 { return nil, fmt.Errorf("%s:%d: 잘못된 cookies.txt 형식입니다", path, lineNo) }
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil { return nil, fmt.Errorf("%s:%d: 잘못된 만료 시각입니다: %s", path, lineNo, fields[4]) }
+
+		domain := strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") { domain = "." + domain }
+		cookie := &http.Cookie{Name: fields[5], Value: fields[6], Domain: domain, Path: fields[2], Secure: strings.EqualFold(fields[3], "TRUE"), HttpOnly: httpOnly}
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+			if cookie.Expires.Before(now) { continue }
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, scanner.Err()
+}
+
+// setupCredentials: Options의 헤더/인증 정보를 정리하고, 초기 쿠키를 담은 쿠키 저장소를 HTTP 클라이언트에 연결합니다.
+// User-Agent는 모든 요청에 쓰고, 나머지 헤더와 Authorization은 사이트 호스트로 가는 요청에만 붙입니다.
+func (m *Mirror) setupCredentials() error {
+	m.userAgent = defaultUserAgent
+	m.siteHeaders = make(http.Header)
+	for name, values := range m.opts.Headers {
+		if len(values) == 0 { continue }
+		if strings.EqualFold(name, "User-Agent") {
+			m.userAgent = values[len(values)-1]
+			continue
+		}
+		for _, v := range values { m.siteHeaders.Add(name, v) }
+	}
+
+	switch {
+	case m.opts.BasicAuth != "" && m.opts.BearerToken != "":
+		return fmt.Errorf("Basic 인증과 Bearer 토큰은 함께 사용할 수 없습니다")
+	case m.opts.BasicAuth != "":
+		if !strings.Contains(m.opts.BasicAuth, ":") { return fmt.Errorf("Basic 인증 정보는 \"사용자:비밀번호\" 형식이어야 합니다") }
+		m.siteHeaders.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(m.opts.BasicAuth)))
+	case m.opts.BearerToken != "":
+		m.siteHeaders.Set("Authorization", "Bearer "+m.opts.BearerToken)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil { return err }
+	for _, c := range m.opts.Cookies {
+		target, subdomains, err := m.cookieTarget(c)
+		if err != nil { return err }
+		jarCookie := *c
+		jarCookie.Domain, jarCookie.Path = "", target.Path
+		if subdomains { jarCookie.Domain = target.Hostname() }
+		jar.SetCookies(target, []*http.Cookie{&jarCookie})
+	}
+	m.httpClient.Jar = jar
+	return nil
+}
+
+// cookieTarget: 쿠키를 설정할 URL과 하위 도메인 포함 여부를 정합니다.
+// Domain이 비어 있으면 시작 URL의 호스트, "."으로 시작하면 하위 도메인 포함, 그 외에는 해당 호스트 전용입니다.
+func (m *Mirror) cookieTarget(c *http.Cookie) (*url.URL, bool, error) {
+	host, subdomains := strings.TrimPrefix(c.Domain, "."), strings.HasPrefix(c.Domain, ".")
+	if host == "" {
+		root, err := url.Parse(m.rootDir)
+		if !m.remote || err != nil { return nil, false, fmt.Errorf("쿠키 %s를 보낼 도메인을 정할 수 없습니다 (도메인 지정 필요)", c.Name) }
+		host = root.Hostname()
+	}
+	scheme := "http"
+	if c.Secure { scheme = "https" }
+	cookiePath := c.Path
+	if cookiePath == "" { cookiePath = "/" }
+	return &url.URL{Scheme: scheme, Host: host, Path: cookiePath}, subdomains, nil
+}
+
+// sendsCredentials: 요청 대상이 추가 헤더와 인증 정보를 보낼 사이트 호스트인지 확인합니다.
+// 크롤링 범위의 호스트(시작 호스트, -scope hosts 목록)이면 경로와 관계없이 보냅니다.
+func (m *Mirror) sendsCredentials(u *url.URL) bool {
+	if m.opts.Scope != ScopePrefix { return m.inScope(u) }
+	root, err := url.Parse(m.rootDir)
+	return err == nil && strings.EqualFold(u.Host, root.Host)
+}
+
+// newRequest: User-Agent와 (사이트 호스트이면) 추가 헤더/인증 정보를 붙인 HTTP 요청을 만듭니다. 쿠키는 httpClient의 저장소가 붙입니다.
+func (m *Mirror) newRequest(ctx context.Context, method string, targetURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, targetURL, nil)
+	if err != nil { return nil, err }
+	req.Header.Set("User-Agent", m.userAgent)
+	if m.sendsCredentials(req.URL) {
+		for name, values := range m.siteHeaders {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+	return req, nil
+}
+
+// ==========================================
+// [Chrome 세션에 같은 신원 적용]
+// ==========================================
+
+// setBrowserCookies: 초기 쿠키를 브라우저에 설정합니다. (브라우저 실행 시 한 번, 모든 탭이 공유)
+func (m *Mirror) setBrowserCookies(ctx context.Context) error {
+	var params []*network.CookieParam
+	for _, c := range m.opts.Cookies {
+		target, subdomains, err := m.cookieTarget(c)
+		if err != nil { return err }
+		param := &network.CookieParam{Name: c.Name, Value: c.Value, Path: target.Path, Secure: c.Secure, HTTPOnly: c.HttpOnly}
+		if subdomains {
+			param.Domain = "." + target.Hostname()
+		} else {
+			param.URL = target.String()
+		}
+		if !c.Expires.IsZero() {
+			expires := cdp.TimeSinceEpoch(c.Expires)
+			param.Expires = &expires
+		}
+		params = append(params, param)
+	}
+	if len(params) == 0 { return nil }
+	return network.SetCookies(params).Do(ctx)
+}
+
+// injectSiteHeaders: 탭에서 사이트 호스트로 가는 요청에 추가 헤더와 인증 정보를 붙입니다.
+// network.SetExtraHTTPHeaders는 모든 호스트(외부 CDN 등)로 보내므로, Fetch 도메인으로 사이트 호스트의 요청만 가로챕니다.
+func (m *Mirror) injectSiteHeaders(ctx context.Context) error {
+	if len(m.siteHeaders) == 0 { return nil }
+	chromedp.ListenTarget(ctx, func(ev any) {
+		paused, ok := ev.(*fetch.EventRequestPaused)
+		if !ok { return }
+		action := fetch.ContinueRequest(paused.RequestID)
+		// 패턴은 호스트만 거르므로 prefix 범위 등은 여기서 한 번 더 확인
+		if u, err := url.Parse(paused.Request.URL); err == nil && m.sendsCredentials(u) {
+			action = action.WithHeaders(m.mergeSiteHeaders(paused.Request.Headers))
+		}
+		// 이벤트 처리 함수 안에서는 명령 응답을 기다릴 수 없으므로 별도 고루틴에서 요청을 이어서 진행
+		go func() {
+			if err := chromedp.Run(ctx, action); err != nil && ctx.Err() == nil {
+				m.logf(" ⚠️  요청 헤더 적용 실패 (%s): %v\n", paused.Request.URL, err)
+			}
+		}()
+	})
+	return chromedp.Run(ctx, fetch.Enable().WithPatterns(m.siteRequestPatterns()))
+}
+
+// siteRequestPatterns: 추가 헤더를 붙일 사이트 호스트의 요청만 가로채는 Fetch 패턴입니다. (요청을 보내기 전 단계)
+// 시작 호스트와 -scope hosts 목록의 호스트(하위 도메인, 모든 포트 포함)가 대상입니다.
+func (m *Mirror) siteRequestPatterns() []*fetch.RequestPattern {
+	var hosts []string
+	if root, err := url.Parse(m.rootDir); err == nil { hosts = append(hosts, root.Host) }
+	if m.opts.Scope == ScopeHosts {
+		for _, allowed := range m.opts.AllowedHosts {
+			allowed = strings.ToLower(strings.TrimSpace(allowed))
+			if allowed == "" { continue }
+			hosts = append(hosts, allowed, allowed+":*", "*."+allowed, "*."+allowed+":*")
+		}
+	}
+	patterns := make([]*fetch.RequestPattern, 0, len(hosts))
+	for _, host := range hosts {
+		patterns = append(patterns, &fetch.RequestPattern{URLPattern: "*://" + host + "/*", RequestStage: fetch.RequestStageRequest})
+	}
+	return patterns
+}
+
+// mergeSiteHeaders: 브라우저의 원래 요청 헤더에 사이트 헤더를 덮어씁니다. (같은 이름은 사이트 헤더가 우선)
+func (m *Mirror) mergeSiteHeaders(original network.Headers) []*fetch.HeaderEntry {
+	var entries []*fetch.HeaderEntry
+	for name, value := range original {
+		if _, ok := m.siteHeaders[http.CanonicalHeaderKey(name)]; ok { continue }
+		entries = append(entries, &fetch.HeaderEntry{Name: name, Value: fmt.Sprint(value)})
+	}
+	for name, values := range m.siteHeaders {
+		for _, v := range values { entries = append(entries, &fetch.HeaderEntry{Name: name, Value: v}) }
+	}
+	return entries
+}
+
+// syncBrowserCookies: 렌더링 중 브라우저가 받은 쿠키(로그인 세션 갱신 등)를 HTTP 클라이언트의 쿠키 저장소에 옮깁니다.
+// 이후 HTTP로 받는 리소스도 브라우저와 같은 세션으로 요청됩니다.
+func (m *Mirror) syncBrowserCookies(ctx context.Context) {
+	var cookies []*network.Cookie
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().Do(ctx)
+		return err
+	}))
+	if err != nil || m.httpClient.Jar == nil { return }
+	for _, c := range cookies {
+		target, subdomains, err := m.cookieTarget(&http.Cookie{Name: c.Name, Domain: c.Domain, Path: c.Path, Secure: c.Secure})
+		if err != nil { continue }
+		cookie := &http.Cookie{Name: c.Name, Value: c.Value, Path: target.Path, Secure: c.Secure, HttpOnly: c.HTTPOnly}
+		if subdomains { cookie.Domain = target.Hostname() }
+		if !c.Session { cookie.Expires = time.Unix(int64(c.Expires), 0) }
+		m.httpClient.Jar.SetCookies(target, []*http.Cookie{cookie})
+	}
+}
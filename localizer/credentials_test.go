@@ -0,0 +1,97 @@
+package localizer
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+)
+
+func TestLoadCookiesFile(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Unix()
+	future := strconv.FormatInt(expires, 10)
+	content := "# Netscape HTTP Cookie File\r\n" +
+		"# comment line\n" +
+		"\n" +
+		".example.com\tTRUE\t/\tFALSE\t" + future + "\tsid\tabc 123\n" +
+		"#HttpOnly_www.example.com\tFALSE\t/app\tTRUE\t0\tsession\txyz\n" +
+		"example.org\tFALSE\t/\tFALSE\t1\texpired\tgone\n" +
+		"example.net\tfalse\t/\tfalse\t0\tempty\n" // 값이 비어 있으면 7번째 필드가 생략될 수 있음
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil { t.Fatal(err) }
+
+	cookies, err := LoadCookiesFile(path)
+	if err != nil { t.Fatal(err) }
+	if len(cookies) != 3 { t.Fatalf("got %d cookies, want 3 (expired one skipped): %v", len(cookies), cookies) }
+
+	sid := cookies[0]
+	if sid.Name != "sid" || sid.Value != "abc 123" || sid.Domain != ".example.com" || sid.Path != "/" || sid.Secure || sid.HttpOnly {
+		t.Errorf("subdomain cookie = %+v", sid)
+	}
+	if sid.Expires.Unix() != expires { t.Errorf("expires = %v, want %d", sid.Expires, expires) }
+
+	session := cookies[1]
+	if session.Name != "session" || session.Value != "xyz" || session.Domain != "www.example.com" || session.Path != "/app" || !session.Secure || !session.HttpOnly {
+		t.Errorf("#HttpOnly_ cookie = %+v", session)
+	}
+	if !session.Expires.IsZero() { t.Errorf("expiry 0 must be a session cookie, got %v", session.Expires) }
+
+	if empty := cookies[2]; empty.Name != "empty" || empty.Value != "" || empty.Domain != "example.net" {
+		t.Errorf("empty-value cookie = %+v", empty)
+	}
+}
+
+func TestLoadCookiesFileErrors(t *testing.T) {
+	for _, content := range []string{
+		"example.com TRUE / FALSE 0 name value\n", // 탭이 아닌 공백 구분
+		"example.com\tTRUE\t/\tFALSE\tsoon\tname\tvalue\n",
+		"example.com\tTRUE\t/\n",
+	} {
+		path := filepath.Join(t.TempDir(), "cookies.txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil { t.Fatal(err) }
+		if _, err := LoadCookiesFile(path); err == nil { t.Errorf("LoadCookiesFile(%q) succeeded, want error", content) }
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		in          string
+		name, value string
+		ok          bool
+	}{
+		{"X-Token: abc", "X-Token", "abc", true},
+		{"x-token:abc:def", "X-Token", "abc:def", true},
+		{"Accept-Language:  ko ", "Accept-Language", "ko", true},
+		{"X-Empty:", "X-Empty", "", true},
+		{"no colon", "", "", false},
+		{": value", "", "", false},
+		{"Bad Name: x", "", "", false},
+	}
+	for _, tt := range tests {
+		name, value, err := ParseHeader(tt.in)
+		if (err == nil) != tt.ok || name != tt.name || value != tt.value {
+			t.Errorf("ParseHeader(%q) = %q, %q, %v", tt.in, name, value, err)
+		}
+	}
+}
+
+func TestSiteRequestPatterns(t *testing.T) {
+	m := &Mirror{rootDir: "https://example.com:8443/docs/", opts: Options{Scope: ScopeHosts, AllowedHosts: []string{"CDN.example.org", " "}}, siteHeaders: http.Header{}}
+	var got []string
+	for _, p := range m.siteRequestPatterns() {
+		if p.RequestStage != fetch.RequestStageRequest { t.Errorf("pattern %s: stage %s", p.URLPattern, p.RequestStage) }
+		got = append(got, p.URLPattern)
+	}
+	want := []string{"*://example.com:8443/*", "*://cdn.example.org/*", "*://cdn.example.org:*/*", "*://*.cdn.example.org/*", "*://*.cdn.example.org:*/*"}
+	if len(got) != len(want) { t.Fatalf("patterns = %q, want %q", got, want) }
+	for i := range want {
+		if got[i] != want[i] { t.Errorf("patterns = %q, want %q", got, want) }
+	}
+
+	m.opts.Scope = ScopeHost
+	if p := m.siteRequestPatterns(); len(p) != 1 || p[0].URLPattern != "*://example.com:8443/*" { t.Errorf("host scope patterns = %v", p) }
+}
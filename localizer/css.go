@@ -0,0 +1,266 @@
+package localizer
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ==========================================
+// [CSS 토크나이저 기반 URL 탐색 및 재작성]
+// ==========================================
+
+// cssRef: CSS 안의 URL 참조 하나입니다.
+type cssRef struct {
+	start, end int    // 원문에서 교체할 바이트 범위 (따옴표 없는 url(...) 전체 또는 문자열 토큰)
+	url        string // 이스케이프를 해제한 URL
+	quote      byte   // 문자열 토큰의 따옴표 (따옴표 없는 url 토큰이면 0)
+	isImport   bool   // @import 대상 (확장자와 관계없이 CSS로 처리)
+}
+
+// URL을 문자열 인자로 받는 함수 (url("a.png"), src("a.png"), image-set("a.png" 1x))
+var cssURLFuncs = map[string]bool{"url": true, "src": true, "image-set": true, "-webkit-image-set": true}
+
+// scanCSSRefs: CSS Syntax Level 3 토큰 규칙에 따라 URL을 담은 구문을 모두 찾습니다.
+//   - url(a.png), url("a.png"), src("a.png")  (괄호·따옴표가 이스케이프된 URL 포함)
+//   - @import "a.css";  @import url(a.css) screen;
+//   - image-set("a.png" 1x, url(b.png) 2x), -webkit-image-set(...)
+// 주석 안의 내용과 @namespace 의 URL은 리소스가 아니므로 제외합니다.
+func scanCSSRefs(css string) []cssRef {
+	var refs []cssRef
+	var funcs []string // 열린 함수/괄호 이름 스택 (일반 괄호는 "")
+	inImport := false  // @import 구문 안 (';' 또는 '{' 까지)
+	inNamespace := false
+
+	i := 0
+	for i < len(css) {
+		c := css[i]
+		switch {
+		case c == '/' && i+1 < len(css) && css[i+1] == '*':
+			end := strings.Index(css[i+2:], "*/")
+			if end == -1 { return refs }
+			i += end + 4
+
+		case c == '"' || c == '\'':
+			val, next := consumeCSSString(css, i)
+			top := ""
+			if len(funcs) > 0 { top = funcs[len(funcs)-1] }
+			if !inNamespace && (cssURLFuncs[top] || (inImport && len(funcs) == 0)) {
+				refs = append(refs, cssRef{start: i, end: next, url: val, quote: c, isImport: inImport})
+			}
+			i = next
+
+		case c == '\\' && isValidEscape(css, i), isNameStart(c), c == '-' && i+1 < len(css) && (isNameStart(css[i+1]) || css[i+1] == '-' || isValidEscape(css, i+1)):
+			name, next := consumeCSSName(css, i)
+			if next >= len(css) || css[next] != '(' {
+				i = next
+				break
+			}
+			lower := strings.ToLower(name)
+			if lower == "url" {
+				// 공백 뒤에 따옴표가 오면 일반 함수(문자열 인자), 아니면 따옴표 없는 url 토큰
+				j := next + 1
+				for j < len(css) && isCSSSpace(css[j]) { j++ }
+				if j >= len(css) || (css[j] != '"' && css[j] != '\'') {
+					val, end, ok := consumeCSSURL(css, next+1)
+					if ok && !inNamespace { refs = append(refs, cssRef{start: i, end: end, url: val, isImport: inImport}) }
+					i = end
+					break
+				}
+			}
+			funcs = append(funcs, lower)
+			i = next + 1
+
+		case c == '@':
+			name, next := consumeCSSName(css, i+1)
+			switch strings.ToLower(name) {
+			case "import":
+				inImport = true
+			case "namespace":
+				inNamespace = true
+			}
+			i = next
+
+		case c == '(':
+			funcs = append(funcs, "")
+			i++
+		case c == ')':
+			if len(funcs) > 0 { funcs = funcs[:len(funcs)-1] }
+			i++
+		case c == ';' || c == '{' || c == '}':
+			inImport, inNamespace = false, false
+			i++
+		default:
+			i++
+		}
+	}
+	return refs
+}
+
+// rewriteCSS: 참조마다 fn을 호출해 새 URL로 바꿉니다. fn이 false를 반환한 참조와
+// 참조 밖의 모든 내용은 원문 바이트 그대로 유지됩니다.
+func rewriteCSS(css string, refs []cssRef, fn func(ref cssRef) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		newURL, ok := fn(ref)
+		if !ok { continue }
+		b.WriteString(css[last:ref.start])
+		if ref.quote == 0 {
+			b.WriteString("url('" + escapeCSSString(newURL, '\'') + "')")
+		} else {
+			b.WriteString(string(ref.quote) + escapeCSSString(newURL, ref.quote) + string(ref.quote))
+		}
+		last = ref.end
+	}
+	if last == 0 { return css }
+	b.WriteString(css[last:])
+	return b.String()
+}
+
+// consumeCSSString: start의 따옴표부터 문자열 토큰을 읽어 값과 토큰 다음 위치를 반환합니다.
+func consumeCSSString(css string, start int) (string, int) {
+	quote := css[start]
+	var b strings.Builder
+	i := start + 1
+	for i < len(css) {
+		c := css[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1
+		case c == '\n':
+			return b.String(), i // 잘못된 문자열 (줄바꿈에서 종료)
+		case c == '\\':
+			if i+1 >= len(css) {
+				i++
+				continue
+			}
+			if css[i+1] == '\n' {
+				i += 2 // 줄 이어쓰기
+				continue
+			}
+			r, next := consumeCSSEscape(css, i+1)
+			b.WriteRune(r)
+			i = next
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), i
+}
+
+// consumeCSSURL: "url(" 다음 위치부터 따옴표 없는 URL을 읽습니다.
+// 잘못된 url 토큰(따옴표, 괄호, 중간 공백 포함)이면 ok=false와 함께 닫는 괄호 다음 위치를 반환합니다.
+func consumeCSSURL(css string, start int) (val string, end int, ok bool) {
+	var b strings.Builder
+	i := start
+	for i < len(css) && isCSSSpace(css[i]) { i++ }
+	for i < len(css) {
+		c := css[i]
+		switch {
+		case c == ')':
+			return b.String(), i + 1, true
+		case isCSSSpace(c):
+			for i < len(css) && isCSSSpace(css[i]) { i++ }
+			if i < len(css) && css[i] == ')' { return b.String(), i + 1, true }
+			return "", skipBadURL(css, i), false
+		case c == '"' || c == '\'' || c == '(':
+			return "", skipBadURL(css, i), false
+		case c == '\\':
+			if !isValidEscape(css, i) { return "", skipBadURL(css, i), false }
+			r, next := consumeCSSEscape(css, i+1)
+			b.WriteRune(r)
+			i = next
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), i, true
+}
+
+// skipBadURL: 잘못된 url 토큰의 나머지를 닫는 괄호까지 건너뜁니다.
+func skipBadURL(css string, i int) int {
+	for i < len(css) {
+		if css[i] == ')' { return i + 1 }
+		if css[i] == '\\' && isValidEscape(css, i) {
+			i += 2
+			continue
+		}
+		i++
+	}
+	return i
+}
+
+// consumeCSSName: 식별자(이스케이프 포함)를 읽어 해제된 이름과 다음 위치를 반환합니다.
+func consumeCSSName(css string, start int) (string, int) {
+	var b strings.Builder
+	i := start
+	for i < len(css) {
+		c := css[i]
+		if isNameChar(c) {
+			b.WriteByte(c)
+			i++
+		} else if c == '\\' && isValidEscape(css, i) {
+			r, next := consumeCSSEscape(css, i+1)
+			b.WriteRune(r)
+			i = next
+		} else {
+			break
+		}
+	}
+	return b.String(), i
+}
+
+// consumeCSSEscape: 역슬래시 다음 위치부터 이스케이프 하나를 해제합니다. (\41 → A, \( → ()
+func consumeCSSEscape(css string, start int) (rune, int) {
+	if start >= len(css) { return utf8.RuneError, start }
+	j := start
+	for j < len(css) && j-start < 6 && isHexDigit(css[j]) { j++ }
+	if j > start {
+		cp, _ := strconv.ParseUint(css[start:j], 16, 32)
+		if j < len(css) && isCSSSpace(css[j]) { j++ } // 16진수 이스케이프 뒤의 공백 하나는 구분자
+		if cp == 0 || cp > utf8.MaxRune || (cp >= 0xD800 && cp <= 0xDFFF) { return utf8.RuneError, j }
+		return rune(cp), j
+	}
+	r, size := utf8.DecodeRuneInString(css[start:])
+	return r, start + size
+}
+
+// escapeCSSString: 따옴표 문자열 안에 넣을 수 있도록 역슬래시, 따옴표, 줄바꿈을 이스케이프합니다.
+func escapeCSSString(s string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == quote:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\a `)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isValidEscape(css string, i int) bool {
+	return i+1 < len(css) && css[i] == '\\' && css[i+1] != '\n'
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9' || c == '-'
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isCSSSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,96 @@
+package localizer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanCSSRefs(t *testing.T) {
+	type ref struct {
+		url      string
+		isImport bool
+	}
+	tests := []struct {
+		name string
+		css  string
+		want []ref
+	}{
+		{"import string", `@import "a.css";`, []ref{{"a.css", true}}},
+		{"import single quote with media", `@import 'a.css' screen and (min-width: 10px);`, []ref{{"a.css", true}}},
+		{"import url", `@import url(a.css);`, []ref{{"a.css", true}}},
+		{"import quoted url", `@IMPORT url( "a.css" ) print;`, []ref{{"a.css", true}}},
+		{"import ends at semicolon", `@import "a.css"; .x { content: "b.css" }`, []ref{{"a.css", true}}},
+		{"url forms", `a { background: url(a.png), url("b.png"), url( 'c.png' ) }`, []ref{{"a.png", false}, {"b.png", false}, {"c.png", false}}},
+		{"font src", `@font-face { src: url(f.woff2) format("woff2"), src("f.ttf") }`, []ref{{"f.woff2", false}, {"f.ttf", false}}},
+		{"image-set", `a { background: image-set("a.png" 1x, url(b.png) 2x, 'c.avif' type("image/avif")) }`, []ref{{"a.png", false}, {"b.png", false}, {"c.avif", false}}},
+		{"webkit image-set", `a { background: -webkit-image-set(url(a.png) 1x, "b.png" 2x) }`, []ref{{"a.png", false}, {"b.png", false}}},
+		{"escaped parens and space", `a { background: url(a\(1\)\ b.png) }`, []ref{{"a(1) b.png", false}}},
+		{"hex escape", `a { background: url(\61 .png) }`, []ref{{"a.png", false}}},
+		{"escaped quote in string", `a { background: url("a\"b.png") }`, []ref{{`a"b.png`, false}}},
+		{"escaped function name", `a { background: u\72l(a.png) }`, []ref{{"a.png", false}}},
+		{"comment with url", `/* url(x.png) @import "y.css"; */ a { background: url(a.png) }`, []ref{{"a.png", false}}},
+		{"unterminated comment", `a { background: url(a.png) } /* url(x.png)`, []ref{{"a.png", false}}},
+		{"url in string is text", `a::before { content: "url(x.png)" }`, nil},
+		{"namespace url", `@namespace svg url(http://www.w3.org/2000/svg); a { background: url(a.png) }`, []ref{{"a.png", false}}},
+		{"namespace string", `@namespace "http://www.w3.org/1999/xhtml"; @import "a.css";`, []ref{{"a.css", true}}},
+		{"bad url token", `a { background: url(a b.png); color: red } b { background: url(c.png) }`, []ref{{"c.png", false}}},
+		{"other function strings", `a { font-family: format("woff"); background: url(a.png) }`, []ref{{"a.png", false}}},
+		{"data uri", `a { background: url(data:image/png;base64,iVBOR=) }`, []ref{{"data:image/png;base64,iVBOR=", false}}},
+	}
+	for _, tt := range tests {
+		var got []ref
+		for _, r := range scanCSSRefs(tt.css) { got = append(got, ref{r.url, r.isImport}) }
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: scanCSSRefs(%q) = %v, want %v", tt.name, tt.css, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteCSS(t *testing.T) {
+	tests := []struct {
+		name string
+		css  string
+		want string
+	}{
+		{"import string", `@import "a.css";`, `@import "local/a.css";`},
+		{"import url", `@import url(a.css) screen;`, `@import url('local/a.css') screen;`},
+		{"quoted url keeps quote", `a{background:url("a.png")}`, `a{background:url("local/a.png")}`},
+		{"image-set", `a{background:image-set("a.png" 1x, url(b.png) 2x)}`, `a{background:image-set("local/a.png" 1x, url('local/b.png') 2x)}`},
+		{"escaped url", `a{background:url(a\(1\).png)}`, `a{background:url('local/a(1).png')}`},
+		{"quote in new url", `a{background:url('it.png')}`, `a{background:url('local/it\'s.png')}`},
+		{"comment and namespace untouched", "/* url(x.png) */\n@namespace url(http://ns);\na{background:url(a.png)}", "/* url(x.png) */\n@namespace url(http://ns);\na{background:url('local/a.png')}"},
+	}
+	for _, tt := range tests {
+		got := rewriteCSS(tt.css, scanCSSRefs(tt.css), func(ref cssRef) (string, bool) {
+			if ref.url == "it.png" { return "local/it's.png", true }
+			return "local/" + ref.url, true
+		})
+		if got != tt.want {
+			t.Errorf("%s: rewriteCSS(%q)\n got %s\nwant %s", tt.name, tt.css, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteCSSUnchanged(t *testing.T) {
+	// 참조가 없거나 모두 건너뛰면 원문 바이트가 그대로 유지되어야 함 (주석, 공백, 잘못된 토큰 포함)
+	inputs := []string{
+		"",
+		"a { color: red }\r\n/* comment */\n@media (min-width: 10px) { b { margin: 0 } }",
+		"@charset \"utf-8\";\n\ta::before { content: \"\\201C\" }  /* url(x.png) */",
+		`a { background: url(a b.png) } b { background: url( c.png ) }`,
+		"@import 'a.css' screen;\nx { background: image-set(\"a.png\" 1x) }\n/* 한글 주석 */",
+	}
+	for _, css := range inputs {
+		if got := rewriteCSS(css, scanCSSRefs(css), func(cssRef) (string, bool) { return "", false }); got != css {
+			t.Errorf("rewriteCSS with no rewrites changed %q to %q", css, got)
+		}
+	}
+	css := "a{color:red}\n"
+	if refs := scanCSSRefs(css); len(refs) != 0 || rewriteCSS(css, refs, nil) != css {
+		t.Errorf("rewriteCSS(%q) without refs is not identity", css)
+	}
+	if !strings.Contains(rewriteCSS("a{background:url(x.png)}", scanCSSRefs("a{background:url(x.png)}"), func(ref cssRef) (string, bool) { return ref.url, ref.url != "x.png" }), "url(x.png)") {
+		t.Error("skipped ref was rewritten")
+	}
+}
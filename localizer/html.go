@@ -0,0 +1,326 @@
+package localizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [HTML 처리 함수들]
+// ==========================================
+
+// processHTMLFile: HTML 파일을 처리하는 핵심 함수. 재귀적으로 호출될 수 있습니다.
+// 호출 전에 schedulePage로 중복 여부를 확인해야 합니다.
+func (m *Mirror) processHTMLFile(ctx context.Context, page pageRef) (err error) {
+	// 작업 취소 확인
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	// 이어서 진행: 이전 실행에서 저장을 마친 페이지는 다시 처리하지 않고 발견했던 링크만 대기열에 추가
+	if links, ok := m.completedPage(page); ok {
+		for _, link := range links { m.enqueuePage(link) }
+		return nil
+	}
+	start := time.Now()
+	defer func() { m.report.addPage(page, err, time.Since(start)) }()
+
+	outputFile := filepath.Join(m.opts.OutputDir, page.OutRel)
+
+	var currentContext string
+	var content []byte
+
+	if m.remote {
+		// 페이지 URL 자체를 기준으로 상대 링크를 해석 (ResolveReference가 디렉토리 처리)
+		targetURL := page.Source
+
+		if m.recording {
+			// 기록 프록시 모드: 브라우저가 받은 HTML을 그대로 사용 (기록되지 않은 페이지는 처리하지 않음)
+			var ok bool
+			content, ok = m.recordedDocument(targetURL)
+			if !ok { return fmt.Errorf("기록되지 않은 페이지입니다: %s", targetURL) }
+		} else if page.OutRel == m.startPage().OutRel && m.renderChan != nil {
+			// 시작 파일인 경우, 미리 실행해둔 고루틴의 결과를 기다림 (최대 페이지 렌더링 제한만큼)
+			renderLimit := m.opts.Timeouts.Render
+			m.logf(" ⏳ 렌더링 결과 대기 중...\n")
+			select {
+			case result := <-m.renderChan:
+				content, err = result.Data, result.Err
+				if err != nil { return fmt.Errorf("Background 렌더링 실패: %w", err) }
+				m.logf(" ✨ 렌더링 데이터 수신 완료\n")
+			case <-afterOrNever(renderLimit):
+				return &TimeoutError{Budget: BudgetRender, Limit: renderLimit}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else {
+			// iframe, 링크 추적 등으로 호출된 경우 동기적으로 렌더링
+			m.logf(" 🖥️  브라우저 렌더링 중... (%s)\n", targetURL)
+			content, err = m.fetchRenderedHTML(ctx, targetURL)
+			if err != nil { return fmt.Errorf("Chrome 렌더링 실패: %w", err) }
+		}
+	} else {
+		// 로컬 파일 읽기
+		inputFile := filepath.Join(m.rootDir, page.Source)
+		content, err = os.ReadFile(inputFile)
+	}
+
+	if err != nil { return fmt.Errorf("HTML 읽기 실패: %w", err) }
+
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil { return err }
+
+	// <base href>가 있으면 모든 상대 링크(리소스, iframe, <a>)를 그 주소 기준으로 해석
+	base := findBase(doc)
+	if base != nil {
+		if b, ok := m.resolveBase(page, getAttr(base, "href")); ok { page.Base = b }
+	}
+	currentContext = m.linkContext(page)
+	var baseRelative []baseRelativeValue
+	if page.Base != "" { baseRelative = snapshotBaseRelative(doc) }
+
+	displayPath := filepath.ToSlash(filepath.Join(m.opts.OutputDir, page.OutRel))
+	if m.opts.Format == FormatArchive { displayPath = page.Source }
+	m.logf(" 📄 %s\n", displayPath)
+	if m.remote { m.archive.addPage(page.Source, htmlTitle(doc)) }
+
+	// DOM 순회하며 리소스 수집 (다운로드는 병렬로 진행되고, 속성 수정은 순회 종료 후 일괄 적용)
+	var tasks taskGroup
+	var links []pageRef          // 발견한 <a href> 대상 (실행 기록용)
+	var localLinks []pageLinkRef // 로컬 경로로 바꾼 페이지 링크 (대상 페이지가 저장되지 않으면 복원)
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		// 루프 내에서도 타임아웃 체크
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if n.Type == html.ElementNode {
+			if n.Data == "script" {
+				m.handleAttribute(ctx, &tasks, n, "src", currentContext, page)
+				links = append(links, m.handleInlineScript(ctx, &tasks, n, currentContext, page)...)
+			}
+			if n.Data == "link" {
+				m.handleAttribute(ctx, &tasks, n, "href", currentContext, page)
+				m.handleSrcset(ctx, &tasks, n, "imagesrcset", currentContext, page)
+			}
+			if n.Data == "img" {
+				m.handleAttribute(ctx, &tasks, n, "src", currentContext, page)
+				m.handleAttribute(ctx, &tasks, n, "data-src", currentContext, page)
+				m.handleSrcset(ctx, &tasks, n, "srcset", currentContext, page)
+				m.handleSrcset(ctx, &tasks, n, "data-srcset", currentContext, page)
+			}
+			if n.Data == "source" {
+				// <picture><source srcset>, <video|audio><source src>
+				m.handleSrcset(ctx, &tasks, n, "srcset", currentContext, page)
+				m.handleSrcset(ctx, &tasks, n, "data-srcset", currentContext, page)
+				if isMediaParent(n) { m.handleMedia(ctx, &tasks, n, "src", currentContext, page) }
+			}
+			if n.Data == "video" || n.Data == "audio" {
+				m.handleMedia(ctx, &tasks, n, "src", currentContext, page)
+				if n.Data == "video" { m.handleAttribute(ctx, &tasks, n, "poster", currentContext, page) }
+			}
+			if n.Data == "track" {
+				// 자막(WebVTT)은 크기 제한 없이 일반 리소스로 처리
+				m.handleAttribute(ctx, &tasks, n, "src", currentContext, page)
+			}
+			if n.Data == "object" {
+				m.handleMedia(ctx, &tasks, n, "data", currentContext, page)
+			}
+			if n.Data == "embed" {
+				m.handleMedia(ctx, &tasks, n, "src", currentContext, page)
+			}
+			if n.Data == "style" {
+				m.handleStyleBlock(ctx, &tasks, n, currentContext, page)
+			}
+			m.handleStyleAttr(ctx, &tasks, n, currentContext, page)
+			if n.Data == "iframe" {
+				localLinks = append(localLinks, m.handleIframe(ctx, n, page)...)
+			}
+			if n.Data == "a" && (m.opts.Depth > 0 || m.recording) {
+				found, rewritten := m.handleAnchor(n, page)
+				links = append(links, found...)
+				localLinks = append(localLinks, rewritten...)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(doc)
+
+	// HTML에서 참조되지 않았지만 브라우저가 로드한 리소스(XHR, 지연 로딩 이미지, JS가 요청한 폰트 등)도 저장
+	// (단일 파일 모드에서는 참조할 곳이 없고, 아카이브 전용 모드에서는 이미 기록되었으므로 제외)
+	if m.remote && m.opts.Format == FormatFolder {
+		for _, u := range m.capturedForPage(page.Source) {
+			tasks.Go(func() func() {
+				m.downloadResource(ctx, u, u, refSource{Page: page.Source, Attribute: "(captured)"})
+				return nil
+			})
+		}
+	}
+	tasks.Wait()
+
+	if ctx.Err() != nil { return ctx.Err() }
+	if m.opts.Format == FormatArchive { return nil }
+	m.relaxOfflinePolicies(doc)
+	if base != nil {
+		m.absolutizeBaseRelative(page, baseRelative)
+		detachBase(base)
+	}
+
+	// 변환된 HTML 저장
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil { return err }
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil { return err }
+
+	if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil { return err }
+	m.updateStats(int64(buf.Len()))
+	m.addPageLinks(page, localLinks)
+	return m.journal.addPage(page, links)
+}
+
+// handleIframe: Iframe 태그를 처리합니다. 대상 페이지를 즉시 처리하고 src를 로컬 경로로 바꿉니다.
+// 로컬 경로로 바꾼 링크를 반환합니다. (다른 곳에서 먼저 등록된 페이지는 아직 저장 전일 수 있음)
+func (m *Mirror) handleIframe(ctx context.Context, n *html.Node, page pageRef) (rewritten []pageLinkRef) {
+	for i, a := range n.Attr {
+		if a.Key == "src" {
+			target, fragment, ok := m.resolvePage(page, a.Val)
+			if !ok { continue }
+			if m.schedulePage(target) {
+				if err := m.processHTMLFile(ctx, target); err != nil { continue }
+			}
+			if link, err := pageLink(page, target, fragment); err == nil {
+				rewritten = append(rewritten, m.newPageLinkRef(page, target, fragment, link, a.Val))
+				n.Attr[i].Val = link
+			}
+		}
+	}
+	return rewritten
+}
+
+// handleAnchor: <a href> 링크를 크롤링 대기열에 추가하고, 미러에 포함될 페이지이면 로컬 경로로 바꿉니다.
+// 발견한 링크 대상(실행 기록용)과 로컬 경로로 바꾼 링크(대상이 저장되지 않으면 복원)를 반환합니다.
+func (m *Mirror) handleAnchor(n *html.Node, page pageRef) (links []pageRef, rewritten []pageLinkRef) {
+	for i, a := range n.Attr {
+		if a.Key == "href" {
+			target, fragment, ok := m.resolvePage(page, a.Val)
+			if !ok { continue }
+			target.Depth = page.Depth + 1
+			m.enqueuePage(target)
+			if target.Depth <= m.opts.Depth { links = append(links, target) }
+			if !m.isScheduled(target) { continue }
+			if link, err := pageLink(page, target, fragment); err == nil {
+				rewritten = append(rewritten, m.newPageLinkRef(page, target, fragment, link, a.Val))
+				n.Attr[i].Val = link
+			}
+		}
+	}
+	return links, rewritten
+}
+
+// handleAttribute: 일반 리소스 속성(src, href)을 처리합니다.
+// 다운로드는 tasks에서 병렬로 실행되며, 속성값 수정은 tasks.Wait 시점에 적용됩니다. (인덱스가 아닌 속성 이름으로 찾아 수정)
+func (m *Mirror) handleAttribute(ctx context.Context, tasks *taskGroup, n *html.Node, attrName string, currentContext string, page pageRef) {
+	for _, a := range n.Attr {
+		if a.Key == attrName {
+			val := strings.TrimSpace(a.Val)
+			if shouldIgnoreLink(val) { continue }
+			integrity := getAttr(n, "integrity")
+
+			tasks.Go(func() func() {
+				resourceRelPath, err := m.downloadResource(ctx, val, currentContext, refSource{page.Source, n.Data, attrName})
+				if err != nil { return nil }
+				// 단일 파일 모드: 스크립트와 스타일시트는 요소 안에 내용을 직접 내장
+				if m.singleFile() && inlinesAsElement(n, attrName) {
+					data, ok := m.inlineContent(ctx, resourceRelPath, make(map[string]bool))
+					if !ok { return nil }
+					return func() { inlineElement(n, data) }
+				}
+				ref, err := m.resourceRef(ctx, m.pageOutputDir(page), resourceRelPath)
+				if err != nil { return nil }
+				// SRI: CSS 경로 변환 등으로 내용이 바뀌었을 수 있으므로 저장된 내용으로 다시 계산
+				if integrity != "" && m.opts.Integrity == IntegrityRecompute {
+					if sri, ok := m.localIntegrity(ctx, integrity, resourceRelPath); ok {
+						return func() {
+							setAttr(n, attrName, ref)
+							setAttr(n, "integrity", sri)
+						}
+					}
+				}
+				return func() { setAttr(n, attrName, ref) }
+			})
+		}
+	}
+}
+
+// handleStyleBlock: <style> 요소의 내용을 CSS 파이프라인으로 처리합니다. (상대 경로는 HTML 파일 위치 기준)
+func (m *Mirror) handleStyleBlock(ctx context.Context, tasks *taskGroup, n *html.Node, currentContext string, page pageRef) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode || len(scanCSSRefs(c.Data)) == 0 { continue }
+		tasks.Go(func() func() {
+			css := m.processInlineCSS(ctx, c.Data, currentContext, page, refSource{Page: page.Source, Element: "style"})
+			return func() { c.Data = css }
+		})
+	}
+}
+
+// handleStyleAttr: style 속성의 url()을 CSS 파이프라인으로 처리합니다. (상대 경로는 HTML 파일 위치 기준)
+func (m *Mirror) handleStyleAttr(ctx context.Context, tasks *taskGroup, n *html.Node, currentContext string, page pageRef) {
+	for _, a := range n.Attr {
+		if a.Key != "style" || len(scanCSSRefs(a.Val)) == 0 { continue }
+		tasks.Go(func() func() {
+			css := m.processInlineCSS(ctx, a.Val, currentContext, page, refSource{page.Source, n.Data, "style"})
+			return func() { setAttr(n, "style", css) }
+		})
+	}
+}
+
+// processInlineCSS: HTML 안의 CSS를 처리합니다. 단일 파일 모드에서는 참조를 data: URI로 펼칩니다.
+func (m *Mirror) processInlineCSS(ctx context.Context, css string, currentContext string, page pageRef, from refSource) string {
+	pageDir := filepath.Dir(page.OutRel)
+	data := m.processCSSContent(ctx, []byte(css), currentContext, pageDir, from)
+	if m.singleFile() { data = m.expandCSSRefs(ctx, data, pageDir, make(map[string]bool)) }
+	return string(data)
+}
+
+// pageOutputDir: 페이지가 저장될 폴더 (리소스 상대 경로 계산 기준)
+func (m *Mirror) pageOutputDir(page pageRef) string {
+	return filepath.Dir(filepath.Join(m.opts.OutputDir, page.OutRel))
+}
+
+// htmlTitle: 문서의 <title> 텍스트를 찾습니다. (아카이브 페이지 목록용)
+func htmlTitle(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "title" && n.FirstChild != nil { return strings.TrimSpace(n.FirstChild.Data) }
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if title := htmlTitle(c); title != "" { return title }
+	}
+	return ""
+}
+
+// shouldIgnoreLink: 수집하지 말아야 할 스키마(data, mailto 등)를 필터링합니다.
+func shouldIgnoreLink(link string) bool {
+	link = strings.TrimSpace(strings.ToLower(link))
+	if link == "" { return true }
+	if strings.HasPrefix(link, "data:") ||
+		strings.HasPrefix(link, "#") ||
+		strings.HasPrefix(link, "about:") ||
+		strings.HasPrefix(link, "javascript:") ||
+		strings.HasPrefix(link, "mailto:") ||
+		strings.HasPrefix(link, "tel:") ||
+		strings.HasPrefix(link, "sms:") ||
+		strings.HasPrefix(link, "chrome:") {
+		return true
+	}
+	return false
+}
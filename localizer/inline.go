@@ -0,0 +1,195 @@
+package localizer
+
+import (
+	"context"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ==========================================
+// [출력 형식 및 단일 파일(리소스 내장) 모드]
+// ==========================================
+
+// Format: 결과물 출력 형식입니다.
+type Format string
+
+const (
+	FormatFolder  Format = "folder"  // 리소스를 assets/, fonts/ 폴더에 파일로 저장 (기본값)
+	FormatSingle  Format = "single"  // 페이지마다 모든 리소스를 내장한 .html 하나로 저장
+	FormatArchive Format = "archive" // 미러 폴더 없이 Options.Archive 에만 기록
+)
+
+// ParseFormat: 문자열을 Format으로 변환합니다. 빈 문자열은 FormatFolder로 취급합니다.
+func ParseFormat(s string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatFolder:
+		return FormatFolder, true
+	case FormatSingle:
+		return FormatSingle, true
+	case FormatArchive:
+		return FormatArchive, true
+	}
+	return "", false
+}
+
+// singleFile: 단일 파일 모드 여부
+func (m *Mirror) singleFile() bool { return m.opts.Format == FormatSingle }
+
+// storedEntry: 저장 경로 하나의 처리 상태. 처리(CSS 내부 변환 포함)와 저장이 끝나면 done이 닫힙니다.
+// 단일 파일 모드에서는 디스크 대신 내장할 내용을 data에 보관합니다.
+type storedEntry struct {
+	once sync.Once
+	done chan struct{}
+	data []byte
+}
+
+// reserveStored: 저장 경로가 확정된 리소스의 자리를 만듭니다. (m.mu를 잡은 상태에서 호출)
+func (m *Mirror) reserveStored(saveRelPath string) {
+	if _, ok := m.stored[saveRelPath]; !ok { m.stored[saveRelPath] = &storedEntry{done: make(chan struct{})} }
+}
+
+// markStored: 리소스 처리가 끝났음을 기다리는 쪽에 알립니다. (같은 경로는 처음 결과만 사용)
+// 단일 파일 모드에서만 내용을 메모리에 보관합니다.
+func (m *Mirror) markStored(saveRelPath string, data []byte) {
+	m.mu.Lock()
+	m.reserveStored(saveRelPath)
+	entry := m.stored[saveRelPath]
+	m.mu.Unlock()
+	entry.once.Do(func() {
+		if m.singleFile() { entry.data = data }
+		close(entry.done)
+	})
+}
+
+// storedData: 리소스 처리가 끝날 때까지 기다려 최종 내용을 반환합니다. (폴더 모드는 저장된 파일을 읽음)
+// 다운로드되지 않은 경로이거나 내용이 남지 않는 아카이브 전용 모드이면 false를 반환합니다.
+// CSS 순환 참조로 인한 교착을 피하기 위해 CSS 처리 중에는 호출하지 않고, HTML에 적용할 때만 사용합니다.
+func (m *Mirror) storedData(ctx context.Context, saveRelPath string) ([]byte, bool) {
+	m.mu.Lock()
+	entry, ok := m.stored[saveRelPath]
+	m.mu.Unlock()
+	if !ok { return nil, false }
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, false
+	}
+	switch m.opts.Format {
+	case FormatSingle:
+		return entry.data, true
+	case FormatFolder:
+		data, err := os.ReadFile(filepath.Join(m.opts.OutputDir, saveRelPath))
+		return data, err == nil
+	}
+	return nil, false
+}
+
+// inlineContent: 리소스 내용을 내장 가능한 형태로 반환합니다.
+// CSS는 내부의 상대 경로 url()을, 스크립트는 import 대상을 data: URI로 펼칩니다.
+// (visiting: 순환 참조 방지용 처리 중인 CSS/스크립트 경로)
+func (m *Mirror) inlineContent(ctx context.Context, saveRelPath string, visiting map[string]bool) ([]byte, bool) {
+	data, ok := m.storedData(ctx, saveRelPath)
+	if !ok { return nil, false }
+	if !hasNestedRefs(saveRelPath) { return data, true }
+
+	visiting[saveRelPath] = true
+	defer delete(visiting, saveRelPath)
+	if isScriptPath(saveRelPath) { return m.expandJSRefs(ctx, data, filepath.Dir(saveRelPath), visiting), true }
+	return m.expandCSSRefs(ctx, data, filepath.Dir(saveRelPath), visiting), true
+}
+
+// expandCSSRefs: processCSSContent 가 저장 경로 기준 상대 경로로 바꿔 둔 url()을 data: URI로 펼칩니다.
+// cssDir: CSS가 위치한 출력 폴더 기준 디렉토리 (HTML 내부 CSS는 HTML 파일의 디렉토리)
+func (m *Mirror) expandCSSRefs(ctx context.Context, data []byte, cssDir string, visiting map[string]bool) []byte {
+	css := string(data)
+	return []byte(rewriteCSS(css, scanCSSRefs(css), func(ref cssRef) (string, bool) {
+		link := strings.TrimSpace(ref.url)
+		if shouldIgnoreLink(link) || strings.Contains(link, "://") || strings.HasPrefix(link, "/") { return "", false }
+		target := filepath.Join(cssDir, filepath.FromSlash(link))
+		if visiting[target] { return "", false } // 순환 @import는 원래 경로 유지
+		content, ok := m.inlineContent(ctx, target, visiting)
+		if !ok { return "", false }
+		return dataURI(target, content), true
+	}))
+}
+
+// dataURI: 리소스 내용을 data: URI로 변환합니다. MIME 타입은 확장자로, 모르면 내용으로 판별합니다.
+// (모듈 스크립트는 JavaScript MIME 타입이 아니면 실행되지 않으므로 미리보기 서버의 형식 표를 먼저 사용)
+func dataURI(name string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	mimeType := previewTypes[ext]
+	if mimeType == "" { mimeType = mime.TypeByExtension(ext) }
+	if mimeType == "" { mimeType = http.DetectContentType(data) }
+	mimeType = strings.ReplaceAll(mimeType, " ", "")
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// inlinesAsElement: 단일 파일 모드에서 속성 대신 요소 내용으로 내장할 대상인지 확인합니다.
+// (<script src>, <link rel="stylesheet" href>)
+// defer/async 스크립트는 실행 순서가 바뀌지 않도록 data: URI로 src만 바꿉니다.
+func inlinesAsElement(n *html.Node, attrName string) bool {
+	switch {
+	case n.Data == "script" && attrName == "src":
+		if strings.EqualFold(getAttr(n, "type"), "module") { return true }
+		return !hasAttr(n, "defer") && !hasAttr(n, "async")
+	case n.Data == "link" && attrName == "href":
+		rel := strings.Fields(strings.ToLower(getAttr(n, "rel")))
+		stylesheet, alternate := false, false
+		for _, r := range rel {
+			if r == "stylesheet" { stylesheet = true }
+			if r == "alternate" { alternate = true }
+		}
+		return stylesheet && !alternate
+	}
+	return false
+}
+
+var (
+	closeScriptRe = regexp.MustCompile(`(?i)</script`)
+	closeStyleRe  = regexp.MustCompile(`(?i)</style`)
+)
+
+// inlineElement: <script src>는 본문을 가진 <script>로, <link rel="stylesheet">는 <style>로 바꿉니다.
+// 내용 중의 닫는 태그 문자열은 요소가 일찍 끝나지 않도록 이스케이프합니다.
+func inlineElement(n *html.Node, data []byte) {
+	text := string(data)
+	var keep []html.Attribute
+	if n.Data == "script" {
+		text = closeScriptRe.ReplaceAllStringFunc(text, func(s string) string { return "<\\/" + s[2:] })
+		for _, a := range n.Attr {
+			if a.Key != "src" && a.Key != "integrity" && a.Key != "crossorigin" { keep = append(keep, a) }
+		}
+	} else {
+		text = closeStyleRe.ReplaceAllStringFunc(text, func(s string) string { return "<\\/" + s[2:] })
+		for _, a := range n.Attr {
+			if a.Key == "media" || a.Key == "id" || a.Key == "nonce" { keep = append(keep, a) }
+		}
+		n.Data, n.DataAtom = "style", atom.Style
+	}
+	n.Attr = keep
+	for c := n.FirstChild; c != nil; c = n.FirstChild { n.RemoveChild(c) }
+	n.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key { return a.Val }
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key { return true }
+	}
+	return false
+}
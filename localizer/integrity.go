@@ -0,0 +1,116 @@
+package localizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [오프라인 사본의 SRI / crossorigin / CSP 처리]
+// ==========================================
+
+// IntegrityMode: 로컬로 바꾼 리소스의 Subresource Integrity(integrity 속성) 처리 방식입니다.
+type IntegrityMode string
+
+const (
+	IntegrityRecompute IntegrityMode = "recompute" // 저장된 내용으로 해시를 다시 계산 (기본값)
+	IntegrityDrop      IntegrityMode = "drop"      // integrity 속성을 제거 (file:// 로 열 때 권장)
+)
+
+// ParseIntegrityMode: 문자열을 IntegrityMode로 변환합니다. 빈 문자열은 IntegrityRecompute로 취급합니다.
+func ParseIntegrityMode(s string) (IntegrityMode, bool) {
+	switch IntegrityMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", IntegrityRecompute:
+		return IntegrityRecompute, true
+	case IntegrityDrop:
+		return IntegrityDrop, true
+	}
+	return "", false
+}
+
+// localIntegrity: 저장된 리소스의 최종 내용(CSS 경로 변환, 단일 파일 모드의 내장 변환 포함)으로 integrity 값을 다시 계산합니다.
+func (m *Mirror) localIntegrity(ctx context.Context, integrity string, saveRelPath string) (string, bool) {
+	var data []byte
+	var ok bool
+	if m.singleFile() {
+		data, ok = m.inlineContent(ctx, saveRelPath, make(map[string]bool))
+	} else {
+		data, ok = m.storedData(ctx, saveRelPath)
+	}
+	if !ok { return "", false }
+	return recomputeIntegrity(integrity, data), true
+}
+
+// recomputeIntegrity: integrity 값의 해시마다 같은 알고리즘으로 data의 해시를 다시 계산합니다.
+// (예: "sha384-AAA sha512-BBB" -> 두 알고리즘 모두 새 해시로 교체, 알 수 없는 알고리즘은 브라우저처럼 무시)
+func recomputeIntegrity(integrity string, data []byte) string {
+	var hashes []string
+	for _, token := range strings.Fields(integrity) {
+		alg, _, _ := strings.Cut(token, "-")
+		alg = strings.ToLower(alg)
+		var sum []byte
+		switch alg {
+		case "sha256":
+			s := sha256.Sum256(data)
+			sum = s[:]
+		case "sha384":
+			s := sha512.Sum384(data)
+			sum = s[:]
+		case "sha512":
+			s := sha512.Sum512(data)
+			sum = s[:]
+		default:
+			continue
+		}
+		h := alg + "-" + base64.StdEncoding.EncodeToString(sum)
+		if !slices.Contains(hashes, h) { hashes = append(hashes, h) }
+	}
+	return strings.Join(hashes, " ")
+}
+
+// relaxOfflinePolicies: 로컬 사본에서 리소스 로딩을 막는 요소를 정리합니다. (모든 속성 수정이 끝난 뒤 호출)
+//   - <meta http-equiv="Content-Security-Policy"> 제거 (로컬 경로, data: URI, 내장 스크립트 차단 방지)
+//   - 로컬 경로로 바뀐 요소의 crossorigin 제거 (file:// 에서는 CORS 요청이 항상 실패)
+//   - IntegrityDrop 이면 로컬 경로로 바뀐 요소의 integrity 제거
+func (m *Mirror) relaxOfflinePolicies(doc *html.Node) {
+	var csp []*html.Node
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.Data == "meta" && strings.EqualFold(strings.TrimSpace(getAttr(n, "http-equiv")), "content-security-policy") {
+				csp = append(csp, n)
+			}
+			if isLocalRef(getAttr(n, "src")) || (n.Data == "link" && isLocalRef(getAttr(n, "href"))) {
+				removeAttr(n, "crossorigin")
+				if m.opts.Integrity == IntegrityDrop { removeAttr(n, "integrity") }
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling { f(c) }
+	}
+	f(doc)
+	for _, n := range csp { n.Parent.RemoveChild(n) }
+}
+
+// isLocalRef: 속성값이 로컬 사본 안의 리소스(상대 경로 또는 data: URI)를 가리키는지 확인합니다.
+func isLocalRef(v string) bool {
+	v = strings.TrimSpace(v)
+	return v != "" && !strings.Contains(v, "://") && !strings.HasPrefix(v, "//")
+}
+
+// setAttr: 속성값을 바꿉니다. taskGroup 결과 반영은 순회 중에 기록한 인덱스 대신 이 함수로 이름을 찾아 수정합니다.
+func setAttr(n *html.Node, key string, val string) {
+	for i := range n.Attr {
+		if n.Attr[i].Key == key { n.Attr[i].Val = val }
+	}
+}
+
+// removeAttr: 속성을 제거합니다. 속성 인덱스가 바뀌므로 taskGroup 적용이 모두 끝난 뒤(taskGroup.After 또는 Wait 이후)에만 사용합니다.
+func removeAttr(n *html.Node, key string) {
+	n.Attr = slices.DeleteFunc(n.Attr, func(a html.Attribute) bool { return a.Key == key })
+}
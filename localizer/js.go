@@ -0,0 +1,758 @@
+package localizer
+
+import (
+	"context"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [JavaScript 렉서 및 모듈 참조 탐색/재작성]
+// ==========================================
+
+// jsTokenKind: JavaScript 토큰 종류
+type jsTokenKind int
+
+const (
+	jsPunct    jsTokenKind = iota // 구두점/연산자
+	jsName                        // 식별자, 키워드 (#private 포함)
+	jsString                      // '...', "..."
+	jsTemplate                    // 템플릿 리터럴 조각 (`...`, `...${ 또는 }...` 사이)
+	jsRegExp                      // /.../flags
+	jsNumber                      // 숫자 리터럴
+)
+
+// jsToken: 주석과 공백을 제외한 토큰 하나입니다.
+type jsToken struct {
+	kind       jsTokenKind
+	start, end int    // 원문 바이트 범위 (문자열/템플릿은 따옴표 포함)
+	text       string // 이름·구두점은 원문, 문자열·템플릿은 이스케이프를 해제한 값
+	quote      byte   // 문자열/템플릿의 따옴표 (', ", `)
+	subst      bool   // 템플릿 조각이 ${...} 치환과 이어져 있음 (치환 없는 `...` 이면 false)
+	exprEnd    bool   // '}'가 객체 리터럴 같은 식을 닫음 (뒤의 '/'는 나눗셈)
+}
+
+// jsBrace: 열린 중괄호의 종류
+type jsBrace int
+
+const (
+	braceBlock jsBrace = iota // 문 블록, 함수/클래스 본문 (닫힌 뒤의 '/'는 정규식)
+	braceExpr                 // 객체 리터럴, 구조 분해 패턴 (닫힌 뒤의 '/'는 나눗셈)
+	braceSubst                // 템플릿 치환 ${
+)
+
+// 이 키워드 뒤의 '/'는 나눗셈이 아니라 정규식 리터럴의 시작
+var jsRegExpKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true, "delete": true,
+	"void": true, "throw": true, "case": true, "do": true, "else": true, "yield": true, "await": true,
+}
+
+// lexJS: 소스를 토큰으로 나눕니다. 주석, 문자열, 템플릿 리터럴(중첩 치환 포함), 정규식 리터럴을
+// 구분하므로 그 안에 있는 따옴표나 키워드를 코드로 오인하지 않습니다. 문법 오류가 있어도 끝까지 진행합니다.
+func lexJS(src string) []jsToken {
+	var toks []jsToken
+	var braces []jsBrace // 열린 중괄호 스택
+	i := 0
+	if strings.HasPrefix(src, "#!") { i = lineEnd(src, 0) } // hashbang
+
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			i = lineEnd(src, i)
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 { return toks }
+			i += end + 4
+		case c == '<' && strings.HasPrefix(src[i:], "<!--"), c == '-' && strings.HasPrefix(src[i:], "-->") && lineStartsAt(src, i):
+			i = lineEnd(src, i) // HTML 형식 주석 (클래식 스크립트)
+
+		case c == '"' || c == '\'':
+			val, next := consumeJSString(src, i)
+			toks = append(toks, jsToken{kind: jsString, start: i, end: next, text: val, quote: c})
+			i = next
+		case c == '`':
+			tok, next := consumeJSTemplate(src, i, i+1)
+			toks = append(toks, tok)
+			if tok.subst { braces = append(braces, braceSubst) }
+			i = next
+
+		case c == '/' && regExpAllowed(src, toks):
+			if next, ok := consumeJSRegExp(src, i); ok {
+				toks = append(toks, jsToken{kind: jsRegExp, start: i, end: next, text: src[i:next]})
+				i = next
+				break
+			}
+			toks = append(toks, jsToken{kind: jsPunct, start: i, end: i + 1, text: "/"})
+			i++
+
+		case isDigit(c) || c == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			next := consumeJSNumber(src, i)
+			toks = append(toks, jsToken{kind: jsNumber, start: i, end: next, text: src[i:next]})
+			i = next
+		case isJSNameStart(c) || c == '#' && i+1 < len(src) && isJSNameStart(src[i+1]):
+			next := i + 1
+			for next < len(src) && isJSNamePart(src[next]) { next++ }
+			toks = append(toks, jsToken{kind: jsName, start: i, end: next, text: src[i:next]})
+			i = next
+
+		case c == '{':
+			kind := braceExpr
+			if braceStartsBlock(src, toks, braces) { kind = braceBlock }
+			braces = append(braces, kind)
+			toks = append(toks, jsToken{kind: jsPunct, start: i, end: i + 1, text: "{"})
+			i++
+		case c == '}':
+			kind := braceBlock
+			if len(braces) > 0 {
+				kind = braces[len(braces)-1]
+				braces = braces[:len(braces)-1]
+			}
+			if kind == braceSubst {
+				// ${...} 치환이 끝나면 템플릿 본문이 이어짐
+				tok, next := consumeJSTemplate(src, i, i+1)
+				toks = append(toks, tok)
+				if tok.subst { braces = append(braces, braceSubst) }
+				i = next
+				break
+			}
+			toks = append(toks, jsToken{kind: jsPunct, start: i, end: i + 1, text: "}", exprEnd: kind == braceExpr})
+			i++
+
+		default:
+			n := 1
+			for _, op := range []string{"...", "?.", "++", "--", "=>"} {
+				if strings.HasPrefix(src[i:], op) && !(op == "?." && i+2 < len(src) && isDigit(src[i+2])) {
+					n = len(op)
+					break
+				}
+			}
+			toks = append(toks, jsToken{kind: jsPunct, start: i, end: i + n, text: src[i : i+n]})
+			i += n
+		}
+	}
+	return toks
+}
+
+// regExpAllowed: 직전 토큰을 보고 '/'가 정규식 리터럴의 시작인지 판단합니다.
+// 값(이름, 숫자, 문자열, 닫는 괄호, 객체 리터럴) 뒤에서는 나눗셈으로 취급합니다.
+// 문 블록을 닫는 '}' 뒤는 새 문의 시작이므로 정규식입니다. (if (x) /re/ 처럼 ')' 뒤의 정규식은 구분하지 못함)
+func regExpAllowed(src string, toks []jsToken) bool {
+	if len(toks) == 0 { return true }
+	prev := toks[len(toks)-1]
+	switch prev.kind {
+	case jsName:
+		return jsRegExpKeywords[prev.text] && !afterDot(toks)
+	case jsPunct:
+		switch prev.text {
+		case ")", "]", "++", "--":
+			return false
+		case "}":
+			return !prev.exprEnd
+		}
+		return true
+	case jsTemplate:
+		return src[prev.end-1] == '{' // `...${ 뒤는 식의 시작
+	}
+	return false
+}
+
+// braceStartsBlock: 직전 토큰을 보고 '{'가 문 블록(함수/클래스 본문 포함)인지, 객체 리터럴 같은 식인지 판단합니다.
+// ':' 뒤는 객체 리터럴 안이면 속성 값(식), 블록 안이면 레이블이나 case 다음의 블록으로 봅니다.
+func braceStartsBlock(src string, toks []jsToken, braces []jsBrace) bool {
+	if len(toks) == 0 { return true }
+	prev := toks[len(toks)-1]
+	switch prev.kind {
+	case jsName:
+		// return {...}, typeof {...} 등 식을 받는 키워드 뒤는 객체 리터럴 (else {, do { 는 블록)
+		return !jsRegExpKeywords[prev.text] || prev.text == "else" || prev.text == "do" || afterDot(toks)
+	case jsPunct:
+		switch prev.text {
+		case ";", "{", "}", ")", "=>":
+			return true
+		case ":":
+			return len(braces) == 0 || braces[len(braces)-1] == braceBlock
+		}
+		return false
+	case jsTemplate:
+		return src[prev.end-1] != '{' // `...${ { 는 객체 리터럴
+	}
+	return true
+}
+
+// afterDot: 마지막 이름 토큰이 속성 이름(obj.return, a?.in)인지 확인합니다.
+func afterDot(toks []jsToken) bool {
+	if len(toks) < 2 { return false }
+	dot := toks[len(toks)-2]
+	return dot.kind == jsPunct && (dot.text == "." || dot.text == "?.")
+}
+
+// consumeJSString: 따옴표 문자열을 읽어 이스케이프를 해제한 값과 다음 위치를 반환합니다.
+func consumeJSString(src string, start int) (string, int) {
+	quote := src[start]
+	var b strings.Builder
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1
+		case c == '\n':
+			return b.String(), i // 닫히지 않은 문자열
+		case c == '\\':
+			i = consumeJSEscape(src, i, &b)
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), i
+}
+
+// consumeJSTemplate: 템플릿 본문을 '`' 또는 '${' 까지 읽습니다. start는 토큰 시작(` 또는 }), i는 본문 시작 위치입니다.
+func consumeJSTemplate(src string, start int, i int) (jsToken, int) {
+	var b strings.Builder
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '`':
+			return jsToken{kind: jsTemplate, start: start, end: i + 1, text: b.String(), quote: '`', subst: src[start] == '}'}, i + 1
+		case c == '$' && i+1 < len(src) && src[i+1] == '{':
+			return jsToken{kind: jsTemplate, start: start, end: i + 2, text: b.String(), quote: '`', subst: true}, i + 2
+		case c == '\\':
+			i = consumeJSEscape(src, i, &b)
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return jsToken{kind: jsTemplate, start: start, end: i, text: b.String(), quote: '`', subst: src[start] == '}'}, i
+}
+
+// consumeJSEscape: '\' 위치의 이스케이프를 해제해 b에 쓰고 다음 위치를 반환합니다.
+func consumeJSEscape(src string, i int, b *strings.Builder) int {
+	if i+1 >= len(src) { return i + 1 }
+	c := src[i+1]
+	switch c {
+	case 'n':
+		b.WriteByte('\n')
+	case 't':
+		b.WriteByte('\t')
+	case 'r':
+		b.WriteByte('\r')
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case '\r':
+		if i+2 < len(src) && src[i+2] == '\n' { return i + 3 } // 줄 이어쓰기
+	case '\n':
+		// 줄 이어쓰기
+	case 'x':
+		if i+4 <= len(src) {
+			if v, err := strconv.ParseUint(src[i+2:i+4], 16, 8); err == nil {
+				b.WriteRune(rune(v))
+				return i + 4
+			}
+		}
+		b.WriteByte(c)
+	case 'u':
+		hex, next := "", i+2
+		if next < len(src) && src[next] == '{' {
+			if end := strings.IndexByte(src[next:], '}'); end != -1 { hex, next = src[next+1:next+end], next+end+1 }
+		} else if next+4 <= len(src) {
+			hex, next = src[next:next+4], next+4
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && hex != "" {
+			b.WriteRune(rune(v))
+			return next
+		}
+		b.WriteByte(c)
+	default:
+		r, size := utf8.DecodeRuneInString(src[i+1:])
+		b.WriteRune(r)
+		return i + 1 + size
+	}
+	return i + 2
+}
+
+// consumeJSRegExp: 정규식 리터럴(문자 클래스 안의 '/' 포함)과 플래그를 읽습니다. 줄이 끝나면 정규식이 아님(ok=false)
+func consumeJSRegExp(src string, start int) (int, bool) {
+	inClass := false
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '\n', '\r':
+			return start, false
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if inClass { continue }
+			i++
+			for i < len(src) && isJSNamePart(src[i]) { i++ }
+			return i, true
+		}
+	}
+	return start, false
+}
+
+// consumeJSNumber: 숫자 리터럴(16진수, 지수, 구분자 _, BigInt n 포함)을 읽습니다.
+func consumeJSNumber(src string, start int) int {
+	hex := strings.HasPrefix(strings.ToLower(src[start:]), "0x")
+	i := start
+	for i < len(src) {
+		c := src[i]
+		if isJSNamePart(c) || c == '.' {
+			i++
+			continue
+		}
+		if (c == '+' || c == '-') && (src[i-1] == 'e' || src[i-1] == 'E') && !hex {
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+func lineEnd(src string, i int) int {
+	if end := strings.IndexAny(src[i:], "\n\r"); end != -1 { return i + end }
+	return len(src)
+}
+
+// lineStartsAt: i 앞이 줄의 시작(공백만 있음)인지 확인합니다. (--> 주석 판별)
+func lineStartsAt(src string, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch src[j] {
+		case '\n', '\r':
+			return true
+		case ' ', '\t':
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isJSNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || c == '\\' || c >= 0x80
+}
+
+func isJSNamePart(c byte) bool { return isJSNameStart(c) || isDigit(c) }
+
+// ==========================================
+// [모듈 지정자 탐색]
+// ==========================================
+
+// jsRef: 스크립트 안의 URL 참조 하나입니다.
+type jsRef struct {
+	tok    jsToken // 교체할 문자열/템플릿 토큰
+	url    string
+	kind       string // 보고서용 구문 이름 ("import", "import()", "new URL()", "import.meta.resolve()")
+	module     bool   // 모듈로 가져오는 대상 (importType이 없으면 스크립트로 재귀 분석)
+	importType string // import 속성의 type 값 (with { type: "json" } 이면 "json", 없으면 빈 문자열)
+}
+
+// scanJSRefs: 모듈 스크립트가 가져오는 URL을 찾습니다.
+//   - import "./a.js";  import x, { y } from "./a.js";  export * from "./a.js";  export { y } from "./a.js"
+//   - import("./chunk.js"), import(`./chunk.js`)  (인자가 문자열 하나인 경우만)
+//   - new URL("./x.wasm", import.meta.url),  import.meta.resolve("./a.js")
+//   - import data from "./a.json" with { type: "json" }  (import 속성이 있으면 그 형식의 리소스로 처리)
+// 모듈 지정자는 상대 경로(./, ../), 루트 경로(/), 절대 URL만 대상으로 하며, 베어 지정자("react")는 import map이
+// 필요하므로 제외합니다. 런타임에 이름을 조합하는 청크(webpack의 청크 ID 맵 등)는 찾을 수 없습니다.
+func scanJSRefs(src string) []jsRef {
+	toks := lexJS(src)
+	var refs []jsRef
+	at := func(i int) jsToken {
+		if i < len(toks) { return toks[i] }
+		return jsToken{kind: -1}
+	}
+	isPunct := func(i int, text string) bool { return at(i).kind == jsPunct && at(i).text == text }
+	isName := func(i int, text string) bool { return at(i).kind == jsName && at(i).text == text }
+	// 치환이 없는 문자열/템플릿 리터럴이 인자 전체인지 ( "x" 뒤가 ')' 또는 ',' )
+	literalArg := func(i int) bool {
+		t := at(i)
+		return (t.kind == jsString || t.kind == jsTemplate && !t.subst) && (isPunct(i+1, ")") || isPunct(i+1, ","))
+	}
+	// importType: 지정자(i) 뒤의 import 속성에서 type 값을 읽습니다.
+	// (정적: from "x" with { type: "json" }, 동적: import("x", { with: { type: "json" } }), 옛 문법 assert 포함)
+	importType := func(i int) string {
+		k := i + 1
+		switch {
+		case isName(k, "with") || isName(k, "assert"):
+			k++
+		case isPunct(k, ",") && isPunct(k+1, "{") && (isName(k+2, "with") || isName(k+2, "assert")) && isPunct(k+3, ":"):
+			k += 4
+		default:
+			return ""
+		}
+		if isPunct(k, "{") && (isName(k+1, "type") || at(k+1).kind == jsString && at(k+1).text == "type") && isPunct(k+2, ":") && at(k+3).kind == jsString {
+			return at(k + 3).text
+		}
+		return ""
+	}
+	add := func(i int, kind string, module bool) {
+		if module && !isModuleSpecifier(toks[i].text) { return }
+		ref := jsRef{tok: toks[i], url: toks[i].text, kind: kind, module: module}
+		if module { ref.importType = importType(i) }
+		refs = append(refs, ref)
+	}
+	// fromClause: import/export 절을 건너뛰어 from "x" 의 문자열 위치를 찾습니다.
+	fromClause := func(i int) int {
+		for j := i; j < len(toks); j++ {
+			t := toks[j]
+			if t.kind == jsName && t.text == "from" && at(j+1).kind == jsString { return j + 1 }
+			if t.kind == jsName || t.kind == jsPunct && strings.Contains("{},*", t.text) { continue }
+			break
+		}
+		return -1
+	}
+
+	for i, t := range toks {
+		if t.kind != jsName { continue }
+		if i > 0 && (isPunct(i-1, ".") || isPunct(i-1, "?.")) { continue } // obj.import 등 속성 이름
+		switch t.text {
+		case "import":
+			switch {
+			case at(i+1).kind == jsString:
+				add(i+1, "import", true)
+			case isPunct(i+1, "("):
+				if literalArg(i + 2) { add(i+2, "import()", true) }
+			case isPunct(i+1, "."):
+				if isName(i+2, "meta") && isPunct(i+3, ".") && isName(i+4, "resolve") && isPunct(i+5, "(") && literalArg(i+6) {
+					add(i+6, "import.meta.resolve()", true)
+				}
+			default:
+				if j := fromClause(i + 1); j != -1 { add(j, "import", true) }
+			}
+		case "export":
+			if isPunct(i+1, "*") || isPunct(i+1, "{") {
+				if j := fromClause(i + 1); j != -1 { add(j, "import", true) }
+			}
+		case "new":
+			if isName(i+1, "URL") && isPunct(i+2, "(") && (at(i+3).kind == jsString || at(i+3).kind == jsTemplate && !at(i+3).subst) && isPunct(i+4, ",") &&
+				isName(i+5, "import") && isPunct(i+6, ".") && isName(i+7, "meta") && isPunct(i+8, ".") && isName(i+9, "url") {
+				add(i+3, "new URL()", false)
+			}
+		}
+	}
+	return refs
+}
+
+// isModuleSpecifier: 브라우저가 import map 없이 해석하는 모듈 지정자인지 확인합니다.
+func isModuleSpecifier(s string) bool {
+	return strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "/") ||
+		strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// rewriteJS: 참조마다 fn을 호출해 새 값으로 바꿉니다. 같은 따옴표를 유지하며, fn이 false를 반환한 참조와
+// 참조 밖의 모든 내용은 원문 바이트 그대로 유지됩니다.
+func rewriteJS(src string, refs []jsRef, fn func(ref jsRef) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		newURL, ok := fn(ref)
+		if !ok { continue }
+		b.WriteString(src[last:ref.tok.start])
+		b.WriteString(string(ref.tok.quote) + escapeJSString(newURL, ref.tok.quote) + string(ref.tok.quote))
+		last = ref.tok.end
+	}
+	if last == 0 { return src }
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// escapeJSString: 문자열 리터럴 안에 넣을 수 있도록 따옴표, 역슬래시, 줄바꿈(템플릿은 ${ 도)을 이스케이프합니다.
+func escapeJSString(s string, quote byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '$' && quote == '`' && i+1 < len(s) && s[i+1] == '{':
+			b.WriteString(`\$`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// isScriptPath: 모듈 참조를 분석할 스크립트 파일인지 확인합니다.
+func isScriptPath(p string) bool {
+	ext := strings.ToLower(filepath.Ext(p))
+	return ext == ".js" || ext == ".mjs"
+}
+
+// 브라우저에서 실행하려면 빌드 도구가 JavaScript로 변환해 제공하는 소스 확장자 (개발 서버의 import "/src/main.ts")
+var moduleSourceExts = map[string]bool{".ts": true, ".tsx": true, ".jsx": true, ".mts": true, ".cts": true, ".vue": true, ".svelte": true, ".coffee": true}
+
+// moduleFileName: import 대상의 저장 파일명을 정합니다. 모듈은 스크립트 MIME 형식으로 제공되어야 하므로
+// 확장자가 없거나(esm.sh/react@18.2.0 처럼 MIME 형식을 알 수 없는 경우 포함) 변환 전 소스 확장자이면 .js를 붙이고,
+// data.json, image.png 처럼 형식이 분명한 파일은 그대로 둡니다.
+func moduleFileName(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if isScriptPath(name) || ext != "" && !moduleSourceExts[ext] && mime.TypeByExtension(ext) != "" { return name }
+	return name + ".js"
+}
+
+// ==========================================
+// [스크립트 처리]
+// ==========================================
+
+// processJSContent: 스크립트가 가져오는 모듈과 리소스(scanJSRefs)를 다운로드하고 저장 경로 기준 상대 경로로 바꿉니다.
+// 가져온 모듈은 확장자와 관계없이 스크립트로 처리하여 재귀적으로 분석합니다. (순환 import는 processedFiles로 종료)
+// 단일 파일 모드의 data: URI 변환은 HTML에 내장할 때 수행합니다. (expandJSRefs)
+// from: 보고서에 남길 참조 위치 (Attribute가 비어 있으면 참조마다 구문 이름)
+func (m *Mirror) processJSContent(ctx context.Context, jsData []byte, contextURL string, jsSavedDir string, from refSource) []byte {
+	if ctx.Err() != nil { return jsData }
+
+	jsStr := string(jsData)
+	refs := scanJSRefs(jsStr)
+	if len(refs) == 0 { return jsData }
+
+	rewritten := m.resolveJSRefs(ctx, refs, contextURL, jsSavedDir, from)
+	return []byte(rewriteJS(jsStr, refs, func(ref jsRef) (string, bool) {
+		relPath, ok := rewritten[strings.TrimSpace(ref.url)]
+		return relPath, ok
+	}))
+}
+
+// resolveJSRefs: scanJSRefs 참조를 다운로드하고, 참조값 -> 저장 경로(jsSavedDir 기준 모듈 지정자) 맵을 반환합니다.
+func (m *Mirror) resolveJSRefs(ctx context.Context, refs []jsRef, contextURL string, jsSavedDir string, from refSource) map[string]string {
+	absJSDir := filepath.Join(m.opts.OutputDir, jsSavedDir)
+	rewritten := make(map[string]string)
+	requested := make(map[string]bool)
+	var tasks taskGroup
+	for _, ref := range refs {
+		link := strings.TrimSpace(ref.url)
+		if shouldIgnoreLink(link) || requested[link] { continue }
+		requested[link] = true
+		refFrom := from
+		if refFrom.Attribute == "" { refFrom.Attribute = ref.kind }
+
+		tasks.Go(func() func() {
+			// with { type: "css" } 는 CSS로, "json" 등 그 밖의 형식은 일반 리소스로 받음
+			opts := resourceOpts{js: ref.module && ref.importType == "", css: ref.importType == "css", from: refFrom}
+			resourcePath, err := m.downloadResourceWith(ctx, link, contextURL, opts)
+			if err != nil { return nil }
+			relPath, err := filepath.Rel(absJSDir, filepath.Join(m.opts.OutputDir, resourcePath))
+			if err != nil { return nil }
+			return func() { rewritten[link] = moduleRelPath(relPath) }
+		})
+	}
+	tasks.Wait()
+	return rewritten
+}
+
+// moduleRelPath: 상대 경로를 모듈 지정자로 씁니다. ("a.js"는 베어 지정자이므로 "./a.js")
+func moduleRelPath(relPath string) string {
+	relPath = filepath.ToSlash(relPath)
+	if strings.HasPrefix(relPath, "../") { return relPath }
+	return "./" + relPath
+}
+
+// expandJSRefs: processJSContent 가 저장 경로 기준 상대 경로로 바꿔 둔 참조를 data: URI로 펼칩니다.
+// (단일 파일 모드, jsDir: 스크립트가 위치한 출력 폴더 기준 디렉토리)
+func (m *Mirror) expandJSRefs(ctx context.Context, data []byte, jsDir string, visiting map[string]bool) []byte {
+	src := string(data)
+	return []byte(rewriteJS(src, scanJSRefs(src), func(ref jsRef) (string, bool) {
+		link := strings.TrimSpace(ref.url)
+		if shouldIgnoreLink(link) || strings.Contains(link, "://") || strings.HasPrefix(link, "/") { return "", false }
+		target := filepath.Join(jsDir, filepath.FromSlash(link))
+		if visiting[target] { return "", false } // 순환 import는 원래 경로 유지
+		content, ok := m.inlineContent(ctx, target, visiting)
+		if !ok { return "", false }
+		return dataURI(target, content), true
+	}))
+}
+
+// ==========================================
+// [인라인 스크립트의 문자열 리터럴 (페이지/리소스 참조)]
+// ==========================================
+
+// 인라인 스크립트 문자열에서 리소스로 취급할 확장자 (스크립트는 import 구문으로만 처리)
+var scriptAssetExts = map[string]bool{
+	".html": true, ".htm": true, ".json": true, ".css": true, ".wasm": true, ".xml": true, ".txt": true, ".csv": true, ".vtt": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".avif": true, ".ico": true, ".bmp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true,
+	".mp4": true, ".webm": true, ".mp3": true, ".ogg": true, ".wav": true,
+}
+
+// scanScriptLiterals: 스크립트의 문자열/템플릿 리터럴(치환 없는 것) 중 리소스 경로로 보이는 값을 찾습니다.
+// 주석 안의 값은 제외하며, 공백이 있거나 알려진 확장자로 끝나지 않는 문자열은 경로로 보지 않습니다.
+func scanScriptLiterals(src string) []jsRef {
+	var refs []jsRef
+	for _, t := range lexJS(src) {
+		if t.kind != jsString && !(t.kind == jsTemplate && !t.subst) { continue }
+		if !looksLikeAssetPath(t.text) { continue }
+		refs = append(refs, jsRef{tok: t, url: t.text, kind: "script"})
+	}
+	return refs
+}
+
+// looksLikeAssetPath: 문자열 전체가 리소스 경로(상대 경로, 루트 경로, http(s) URL)인지 확인합니다.
+func looksLikeAssetPath(s string) bool {
+	if s == "" || len(s) > 2048 || shouldIgnoreLink(s) || strings.ContainsAny(s, " \t\r\n\"'`<>{}|\\^") { return false }
+	if strings.Contains(s, "://") && !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") { return false }
+	return scriptAssetExts[strings.ToLower(path.Ext(stripQuery(s)))]
+}
+
+// isPageExt: 스크립트 문자열 중 페이지로 처리할 대상인지 확인합니다.
+func isPageExt(s string) bool {
+	ext := strings.ToLower(path.Ext(stripQuery(s)))
+	return ext == ".html" || ext == ".htm"
+}
+
+// handleInlineScript: 인라인 <script>의 내용을 처리합니다. (상대 경로는 HTML 문서 기준)
+//   - type="module" 이면 import 대상을 받아 로컬 경로로 바꿉니다. (processJSContent)
+//   - 문자열 리터럴 중 같은 사이트의 .html 은 페이지로 처리하고 (로컬: 즉시 처리, 원격: -depth 안에서 대기열에 추가),
+//     그 밖의 리소스(json, 이미지, wasm 등)는 다운로드하여 로컬 경로로 바꿉니다.
+// 페이지 처리는 DOM 순회 중에 수행하고, 다운로드와 내용 변경은 tasks에서 진행합니다. 대기열에 추가한 페이지를 반환합니다.
+func (m *Mirror) handleInlineScript(ctx context.Context, tasks *taskGroup, n *html.Node, currentContext string, page pageRef) (links []pageRef) {
+	scriptType := strings.ToLower(strings.TrimSpace(getAttr(n, "type")))
+	if hasAttr(n, "src") || !scannedScriptType(scriptType) { return nil }
+	module := scriptType == "module"
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode { continue }
+		literals := scanScriptLiterals(c.Data)
+		for _, ref := range literals {
+			if ctx.Err() != nil { return links }
+			if !isPageExt(ref.url) { continue }
+			target, _, ok := m.resolvePage(page, ref.url)
+			if !ok { continue }
+			if !m.remote {
+				if m.schedulePage(target) { m.processHTMLFile(ctx, target) }
+				continue
+			}
+			target.Depth = page.Depth + 1
+			m.enqueuePage(target)
+			if target.Depth <= m.opts.Depth { links = append(links, target) }
+		}
+		if len(literals) == 0 && !(module && len(scanJSRefs(c.Data)) > 0) { continue }
+
+		tasks.Go(func() func() {
+			text := m.rewriteInlineScript(ctx, c.Data, module, currentContext, page)
+			return func() { c.Data = text }
+		})
+	}
+	return links
+}
+
+// rewriteInlineScript: 인라인 스크립트의 모듈 참조(모듈 스크립트)와 문자열 리터럴을 원문 기준으로 한 번에 바꿉니다.
+// import 지정자 자리는 모듈로만 처리하여, 바꾼 결과를 리터럴로 다시 읽어 잘못된 주소를 요청하지 않습니다.
+func (m *Mirror) rewriteInlineScript(ctx context.Context, src string, module bool, currentContext string, page pageRef) string {
+	pageDir := filepath.Dir(page.OutRel)
+	var moduleRefs []jsRef
+	if module { moduleRefs = scanJSRefs(src) }
+	isModuleRef := make(map[int]bool)
+	for _, ref := range moduleRefs { isModuleRef[ref.tok.start] = true }
+	var literals []jsRef
+	for _, ref := range scanScriptLiterals(src) {
+		if !isModuleRef[ref.tok.start] { literals = append(literals, ref) }
+	}
+
+	// 토큰 위치 -> 새 값 (두 목록을 원문 순서로 합쳐 한 번에 교체)
+	replace := make(map[int]string)
+	if len(moduleRefs) > 0 {
+		rewritten := m.resolveJSRefs(ctx, moduleRefs, currentContext, pageDir, refSource{Page: page.Source, Element: "script"})
+		for _, ref := range moduleRefs {
+			if v, ok := rewritten[strings.TrimSpace(ref.url)]; ok { replace[ref.tok.start] = v }
+		}
+	}
+	if len(literals) > 0 {
+		rewritten := m.resolveScriptLiterals(ctx, literals, currentContext, page)
+		for _, ref := range literals {
+			if v, ok := rewritten[ref.url]; ok { replace[ref.tok.start] = v }
+		}
+	}
+	refs := append(moduleRefs, literals...)
+	sort.Slice(refs, func(i, j int) bool { return refs[i].tok.start < refs[j].tok.start })
+	text := rewriteJS(src, refs, func(ref jsRef) (string, bool) {
+		v, ok := replace[ref.tok.start]
+		return v, ok
+	})
+	if module && m.singleFile() { text = string(m.expandJSRefs(ctx, []byte(text), pageDir, make(map[string]bool))) }
+	return text
+}
+
+// scannedScriptType: 내용을 분석할 <script type> 인지 확인합니다. (JavaScript, 모듈, JSON 데이터/import map)
+// text/template 처럼 HTML 조각을 담는 형식은 JavaScript로 읽을 수 없으므로 제외합니다.
+func scannedScriptType(t string) bool {
+	if i := strings.IndexByte(t, ';'); i != -1 { t = strings.TrimSpace(t[:i]) }
+	return t == "" || t == "module" || t == "importmap" || strings.Contains(t, "javascript") || strings.Contains(t, "ecmascript") || strings.Contains(t, "json")
+}
+
+// resolveScriptLiterals: 스크립트 문자열 중 페이지는 저장된 페이지 경로로, 같은 사이트의 리소스는 다운로드한 로컬 경로로
+// 바꿀 값을 찾아 참조값 -> 새 값 맵으로 반환합니다. 다른 호스트의 리소스와 받지 못한 리소스는 포함하지 않습니다. (원래 값 유지)
+func (m *Mirror) resolveScriptLiterals(ctx context.Context, refs []jsRef, currentContext string, page pageRef) map[string]string {
+	rewritten := make(map[string]string)
+	requested := make(map[string]bool)
+	var tasks taskGroup
+	for _, ref := range refs {
+		link := ref.url
+		if requested[link] { continue }
+		requested[link] = true
+
+		if isPageExt(link) {
+			target, fragment, ok := m.resolvePage(page, link)
+			if !ok || !m.isScheduled(target) { continue }
+			if rel, err := pageLink(page, target, fragment); err == nil { rewritten[link] = rel }
+			continue
+		}
+		if !m.sameSite(link, currentContext, page) { continue }
+		tasks.Go(func() func() {
+			resourcePath, err := m.downloadResource(ctx, link, currentContext, refSource{page.Source, "script", "(string)"})
+			if err != nil { return nil }
+			ref, err := m.resourceRef(ctx, m.pageOutputDir(page), resourcePath)
+			if err != nil { return nil }
+			return func() { rewritten[link] = ref }
+		})
+	}
+	tasks.Wait()
+	return rewritten
+}
+
+// sameSite: 스크립트 문자열이 가리키는 리소스가 미러 대상 사이트(시작 호스트 또는 현재 페이지 호스트)에 있는지 확인합니다.
+// 로컬 모드에서는 루트 폴더 안에 실제로 있는 파일만 대상으로 합니다.
+func (m *Mirror) sameSite(link string, currentContext string, page pageRef) bool {
+	if !m.remote {
+		if strings.HasPrefix(link, "http") || strings.HasPrefix(link, "//") || strings.HasPrefix(currentContext, "http") { return false }
+		rel := localPath(currentContext, stripQuery(link))
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) { return false }
+		return fileExists(filepath.Join(m.rootDir, rel))
+	}
+	base, err := url.Parse(currentContext)
+	if err != nil { return false }
+	ref, err := url.Parse(link)
+	if err != nil { return false }
+	target := base.ResolveReference(ref)
+	root, _ := url.Parse(m.rootDir)
+	pageURL, _ := url.Parse(page.Source)
+	return root != nil && strings.EqualFold(target.Host, root.Host) || pageURL != nil && strings.EqualFold(target.Host, pageURL.Host)
+}
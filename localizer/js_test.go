@@ -0,0 +1,176 @@
+package localizer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestModuleFileName(t *testing.T) {
+	tests := map[string]string{
+		"main.js":      "main.js",
+		"lib.mjs":      "lib.mjs",
+		"react@18.2.0": "react@18.2.0.js",
+		"util":         "util.js",
+		"index.min":    "index.min.js",
+		"main.ts":      "main.ts.js",
+		"App.tsx":      "App.tsx.js",
+		"Comp.vue":     "Comp.vue.js",
+		"data.json":    "data.json",
+		"style.css":    "style.css",
+		"module.wasm":  "module.wasm",
+		"icon.svg":     "icon.svg",
+	}
+	for in, want := range tests {
+		if got := moduleFileName(in); got != want {
+			t.Errorf("moduleFileName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestScanJSRefsImportType(t *testing.T) {
+	src := `import a from "./a.json" with { type: "json" };
+import b from "./b.css" assert { "type": "css" };
+import c from "./c.js";
+import "./d.js" with { type: "json" };
+export { e } from "./e.js";
+const f = import("./f.json", { with: { type: "json" } });
+const g = import("./g.js");
+const h = new URL("./h.wasm", import.meta.url);`
+	want := []struct{ url, importType string }{
+		{"./a.json", "json"}, {"./b.css", "css"}, {"./c.js", ""}, {"./d.js", "json"}, {"./e.js", ""}, {"./f.json", "json"}, {"./g.js", ""}, {"./h.wasm", ""},
+	}
+	refs := scanJSRefs(src)
+	if len(refs) != len(want) { t.Fatalf("scanJSRefs found %d refs, want %d: %+v", len(refs), len(want), refs) }
+	for i, ref := range refs {
+		if ref.url != want[i].url || ref.importType != want[i].importType {
+			t.Errorf("ref %d = (%q, %q), want (%q, %q)", i, ref.url, ref.importType, want[i].url, want[i].importType)
+		}
+	}
+}
+
+// formatTokens: 토큰 목록을 비교하기 쉬운 문자열로 만듭니다. (정규식은 re(...), 문자열은 "...", 템플릿 조각은 `...`)
+func formatTokens(toks []jsToken) string {
+	parts := make([]string, len(toks))
+	for i, t := range toks {
+		switch t.kind {
+		case jsRegExp:
+			parts[i] = "re(" + t.text + ")"
+		case jsString:
+			parts[i] = `"` + t.text + `"`
+		case jsTemplate:
+			parts[i] = "`" + t.text + "`"
+		default:
+			parts[i] = t.text
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLexJS(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		// 나눗셈 / 정규식 구분
+		{"division after names", `a / b / c`, `a / b / c`},
+		{"regexp after assignment", `x = /a\/b[/]/gi`, `x = re(/a\/b[/]/gi)`},
+		{"division after paren", `(a + b) / 2 / c`, `( a + b ) / 2 / c`},
+		{"division after bracket", `a[0] / b[1] / 2`, `a [ 0 ] / b [ 1 ] / 2`},
+		{"division after postfix", `i++ / 2`, `i ++ / 2`},
+		{"division after object in parens", `({}) / 2 / x`, `( { } ) / 2 / x`},
+		{"division after object literal", `x = {} / y / z`, `x = { } / y / z`},
+		{"division after nested object", `x = {a: {b: 1}} / y / z`, `x = { a : { b : 1 } } / y / z`},
+		{"division after returned object", `return {a} / y / z`, `return { a } / y / z`},
+		{"regexp after block", `if (x) {} /re/.test(y)`, `if ( x ) { } re(/re/) . test ( y )`},
+		{"regexp after function body", "function f() {}\n/re/g.exec(s)", "function f ( ) { } re(/re/g) . exec ( s )"},
+		{"regexp after case block", `switch (x) { case 1: {} /a/.test(y) }`, `switch ( x ) { case 1 : { } re(/a/) . test ( y ) }`},
+		{"regexp after keyword", `return /re/.test(x)`, `return re(/re/) . test ( x )`},
+		{"division after keyword property", `obj.return / 2 / x`, `obj . return / 2 / x`},
+		{"division after number and string", `1 / 2, "a" / 3 / b`, `1 / 2 , "a" / 3 / b`},
+		// 템플릿 리터럴 (중첩 치환)
+		{"template with object", "`a${ {b: 1}.b / 2 / c }d`", "`a` { b : 1 } . b / 2 / c `d`"},
+		{"nested template", "`a${`b${c}`}d` / 2 / e", "`a` `b` c `` `d` / 2 / e"},
+		{"division after template", "`x` / 2 / y", "`x` / 2 / y"},
+		{"regexp in substitution", "`${ /re/.source }`", "`` re(/re/) . source ``"},
+		{"braces in substitution block", "`${ (() => { return 1 })() }`", "`` ( ( ) => { return 1 } ) ( ) ``"},
+		// 주석과 문자열
+		{"line comment", "a // /re/ \"x\" `y`\n/ b / c", `a / b / c`},
+		{"block comment", `/* "x" /re/ */ "y"`, `"y"`},
+		{"html comment", "<!-- x\na\n--> y\nb", `a b`},
+		{"string escapes", `'a\'b' "cA\x42"`, `"a'b" "cAB"`},
+		{"unterminated regexp", "a = / b\nc", `a = / b c`},
+	}
+	for _, tt := range tests {
+		if got := formatTokens(lexJS(tt.src)); got != tt.want {
+			t.Errorf("%s: lexJS(%q)\n got %s\nwant %s", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestScanJSRefs(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{`import a from "./a.js"; import "./b.js"; export * from "../c.js"; export { d } from "/d.js"`, []string{"./a.js", "./b.js", "../c.js", "/d.js"}},
+		{`import x, { y as z } from "https://cdn.example.com/m.js"`, []string{"https://cdn.example.com/m.js"}},
+		{"import(`./t.js`); import(\"./u.js\"); import(`./${name}.js`); import(base + \"./v.js\")", []string{"./t.js", "./u.js"}},
+		{`obj.import("./x.js"); a?.import("./y.js"); import("./z.js")`, []string{"./z.js"}},
+		{`import React from "react"; import("lodash")`, nil}, // 베어 지정자
+		{`// import "./c.js"` + "\n" + `/* import("./d.js") */ const s = "import './e.js'"`, nil},
+		{`x = a / 2; import("./f.js") / 1`, []string{"./f.js"}},
+		{`const w = new URL("./w.wasm", import.meta.url); import.meta.resolve("./r.js")`, []string{"./w.wasm", "./r.js"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, ref := range scanJSRefs(tt.src) { got = append(got, ref.url) }
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scanJSRefs(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteInlineModuleScript(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/m.js":
+			w.Header().Set("Content-Type", "text/javascript")
+			io.WriteString(w, "export const a = 1")
+		case "/x.wasm", "/data.json", "/pic.png":
+			io.WriteString(w, "data")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	m, err := New(Options{Source: srv.URL + "/", OutputDir: t.TempDir()})
+	if err != nil { t.Fatal(err) }
+	defer m.Close()
+	src := `import { a } from "./m.js";
+const w = new URL("./x.wasm", import.meta.url);
+fetch("data.json"); const img = "./pic.png";`
+	page := pageRef{Source: srv.URL + "/index.html", OutRel: "index.html"}
+	got := m.rewriteInlineScript(context.Background(), src, true, page.Source, page)
+
+	for _, want := range []string{`from "./assets/`, `new URL("./assets/`, `fetch("assets/`, `img = "assets/`} {
+		if !strings.Contains(got, want) { t.Errorf("rewritten script lacks %q:\n%s", want, got) }
+	}
+	// 모듈로 바꾼 지정자를 리터럴로 다시 읽어 요청하면 안 됨
+	for _, p := range requested {
+		if strings.HasPrefix(p, "/assets/") { t.Errorf("requested rewritten path %s", p) }
+	}
+	if failed := m.result().Failed; failed != 0 { t.Errorf("%d failed references, requested %q", failed, requested) }
+}
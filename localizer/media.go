@@ -0,0 +1,167 @@
+package localizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ==========================================
+// [미디어 요소 (video, audio, track, object, embed)]
+// ==========================================
+
+// OversizePolicy: Options.MaxMediaSize 를 넘는 미디어의 처리 방식입니다.
+type OversizePolicy string
+
+const (
+	OversizeSkip        OversizePolicy = "skip"        // 받지 않고 원본 URL을 유지 (기본값)
+	OversizePlaceholder OversizePolicy = "placeholder" // 미디어 요소를 원본 링크가 담긴 안내 상자로 교체
+)
+
+// ParseOversizePolicy: 문자열을 OversizePolicy로 변환합니다. 빈 문자열은 OversizeSkip으로 취급합니다.
+func ParseOversizePolicy(s string) (OversizePolicy, bool) {
+	switch OversizePolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", OversizeSkip:
+		return OversizeSkip, true
+	case OversizePlaceholder:
+		return OversizePlaceholder, true
+	}
+	return "", false
+}
+
+// sizeLimitError: 리소스가 크기 제한을 넘어 다운로드하지 않았음을 나타냅니다.
+type sizeLimitError struct {
+	Size  int64 // 실제 크기 (알 수 없으면 0)
+	Limit int64
+}
+
+func (e *sizeLimitError) Error() string {
+	if e.Size <= 0 { return fmt.Sprintf("크기 제한 초과 (> %s)", formatSize(e.Limit)) }
+	return fmt.Sprintf("크기 제한 초과 (%s > %s)", formatSize(e.Size), formatSize(e.Limit))
+}
+
+// handleMedia: 미디어 URL 속성(video/audio/source[src], object[data], embed[src])을 처리합니다.
+// Options.MaxMediaSize 를 넘는 미디어는 받지 않으며, OversizePlaceholder 이면 요소를 안내 상자로 바꿉니다.
+func (m *Mirror) handleMedia(ctx context.Context, tasks *taskGroup, n *html.Node, attrName string, currentContext string, page pageRef) {
+	for _, a := range n.Attr {
+		if a.Key != attrName { continue }
+		val := strings.TrimSpace(a.Val)
+		if shouldIgnoreLink(val) { continue }
+
+		tasks.Go(func() func() {
+			resourceRelPath, err := m.downloadResourceWith(ctx, val, currentContext, resourceOpts{maxBytes: m.opts.MaxMediaSize, from: refSource{page.Source, n.Data, attrName}})
+			var tooLarge *sizeLimitError
+			if errors.As(err, &tooLarge) {
+				m.logf("           └── ⏭️  미디어 생략 (%v): %s\n", tooLarge, val)
+				if m.opts.OversizeMedia != OversizePlaceholder { return nil }
+				// 속성/요소 제거는 같은 요소의 다른 결과 반영(poster 등)이 모두 끝난 뒤에 수행
+				link := absoluteLink(val, currentContext)
+				return func() { tasks.After(func() { replaceWithPlaceholder(n, link, tooLarge) }) }
+			}
+			if err != nil { return nil }
+			ref, err := m.resourceRef(ctx, m.pageOutputDir(page), resourceRelPath)
+			if err != nil { return nil }
+			return func() { setAttr(n, attrName, ref) }
+		})
+	}
+}
+
+// replaceWithPlaceholder: 미디어 요소를 크기와 원본 링크를 보여주는 <div>로 교체합니다.
+// 재생 후보가 여럿인 <video>/<audio>는 크기를 넘은 후보(src 속성 또는 <source>)만 제거하고,
+// 재생할 후보가 하나도 남지 않았을 때만 요소 전체를 교체합니다.
+func replaceWithPlaceholder(n *html.Node, link string, tooLarge *sizeLimitError) {
+	target := n
+	switch {
+	case n.Data == "source" && isMediaParent(n):
+		target = n.Parent
+		target.RemoveChild(n)
+		if hasPlayableMedia(target) { return }
+	case n.Data == "video" || n.Data == "audio":
+		removeAttr(n, "src")
+		if hasPlayableMedia(n) { return }
+	}
+	if target.Parent == nil { return }
+
+	style := "display:flex;align-items:center;justify-content:center;gap:.5em;box-sizing:border-box;padding:1em;background:#222;color:#ddd;font:14px sans-serif"
+	if w, err := strconv.Atoi(getAttr(target, "width")); err == nil { style += fmt.Sprintf(";width:%dpx", w) }
+	if h, err := strconv.Atoi(getAttr(target, "height")); err == nil { style += fmt.Sprintf(";height:%dpx", h) }
+
+	div := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div, Attr: []html.Attribute{
+		{Key: "class", Val: "localizer-media-placeholder"},
+		{Key: "style", Val: style},
+	}}
+	div.AppendChild(&html.Node{Type: html.TextNode, Data: fmt.Sprintf("<%s> 생략됨 - %v ", target.Data, tooLarge)})
+	anchor := &html.Node{Type: html.ElementNode, Data: "a", DataAtom: atom.A, Attr: []html.Attribute{{Key: "href", Val: link}}}
+	anchor.AppendChild(&html.Node{Type: html.TextNode, Data: "원본 보기"})
+	div.AppendChild(anchor)
+
+	target.Parent.InsertBefore(div, target)
+	target.Parent.RemoveChild(target)
+}
+
+// absoluteLink: 원격 모드에서는 원본 링크를 페이지 URL 기준의 절대 URL로 바꿉니다. (안내 상자의 원본 링크용)
+func absoluteLink(link string, currentContext string) string {
+	if !strings.HasPrefix(currentContext, "http") { return link }
+	base, err := url.Parse(currentContext)
+	if err != nil { return link }
+	ref, err := url.Parse(link)
+	if err != nil { return link }
+	return base.ResolveReference(ref).String()
+}
+
+// hasPlayableMedia: <video>/<audio>에 재생 후보(src 속성 또는 <source> 자식)가 남아 있는지 확인합니다.
+func hasPlayableMedia(n *html.Node) bool {
+	if hasAttr(n, "src") { return true }
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "source" && hasAttr(c, "src") { return true }
+	}
+	return false
+}
+
+// isMediaParent: <source>가 미디어 요소(<video>, <audio>) 안에 있는지 확인합니다. (<picture>의 source는 srcset 사용)
+func isMediaParent(n *html.Node) bool {
+	return n.Parent != nil && (n.Parent.Data == "video" || n.Parent.Data == "audio")
+}
+
+// formatSize: 바이트 수를 읽기 쉬운 단위로 표시합니다. (예: 1.5 GB)
+func formatSize(n int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	size := float64(n)
+	i := 0
+	for size >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 { return fmt.Sprintf("%d B", n) }
+	return fmt.Sprintf("%.1f %s", size, units[i])
+}
+
+// ParseSize: "500MB", "1.5G", "4096" 형식의 크기 문자열을 바이트 수로 변환합니다. (1KB = 1024B)
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" { return 0, nil }
+	num := strings.TrimRight(strings.TrimSuffix(s, "B"), "KMGT")
+	mult := int64(1)
+	switch strings.TrimSuffix(strings.TrimPrefix(s, num), "B") {
+	case "":
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	case "T":
+		mult = 1 << 40
+	default:
+		return 0, fmt.Errorf("잘못된 크기 형식입니다: %s", s)
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || v < 0 { return 0, fmt.Errorf("잘못된 크기 형식입니다: %s", s) }
+	return int64(v * float64(mult)), nil
+}
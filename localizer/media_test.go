@@ -0,0 +1,84 @@
+package localizer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestReplaceWithPlaceholder(t *testing.T) {
+	tooLarge := &sizeLimitError{Size: 2 << 20, Limit: 1 << 20}
+	tests := []struct {
+		name     string
+		body     string
+		oversize []string // 크기를 넘은 요소 (src 값으로 찾음, 순서대로 적용)
+		want     string   // 미디어 요소 대신 남아야 하는 내용 (placeholder 이면 안내 상자)
+	}{
+		{"video src with fitting source", `<video src="big.mp4"><source src="small.webm"/></video>`, []string{"big.mp4"}, `<video><source src="small.webm"/></video>`},
+		{"video src only", `<video src="big.mp4"></video>`, []string{"big.mp4"}, "placeholder"},
+		{"one of two sources", `<video><source src="big.mp4"/><source src="small.webm"/></video>`, []string{"big.mp4"}, `<video><source src="small.webm"/></video>`},
+		{"source with fitting src", `<audio src="small.mp3"><source src="big.ogg"/></audio>`, []string{"big.ogg"}, `<audio src="small.mp3"></audio>`},
+		{"src then last source", `<video src="a.mp4"><source src="b.webm"/></video>`, []string{"a.mp4", "b.webm"}, "placeholder"},
+		{"last source then src", `<video src="a.mp4"><source src="b.webm"/></video>`, []string{"b.webm", "a.mp4"}, "placeholder"},
+	}
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader("<html><body>" + tt.body + "</body></html>"))
+		if err != nil { t.Fatal(err) }
+		for _, src := range tt.oversize {
+			n := findBySrc(doc, src)
+			if n == nil { t.Fatalf("%s: no element with src %q", tt.name, src) }
+			replaceWithPlaceholder(n, "https://example.com/"+src, tooLarge)
+		}
+
+		body := findBySrc(doc, "")
+		var buf bytes.Buffer
+		for c := body.FirstChild; c != nil; c = c.NextSibling { html.Render(&buf, c) }
+		got := buf.String()
+		if tt.want == "placeholder" {
+			if !strings.Contains(got, `class="localizer-media-placeholder"`) || strings.Contains(got, "<video") || strings.Contains(got, "<audio") {
+				t.Errorf("%s: got %s, want placeholder", tt.name, got)
+			}
+		} else if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOversizeVideoKeepsPoster(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"index.html": `<html><head></head><body><video src="big.mp4" poster="hero.jpg" width="100"><source src="ok.mp4"></video></body></html>`,
+		"big.mp4":    strings.Repeat("0", 100),
+		"ok.mp4":     "ok",
+		"hero.jpg":   "jpg",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(data), 0644); err != nil { t.Fatal(err) }
+	}
+	want := `<html><head></head><body><video poster="assets/hero.jpg" width="100"><source src="assets/ok.mp4"/></video></body></html>`
+
+	// 결과 반영 순서가 완료 순서에 좌우되지 않는지 여러 번 실행해 확인
+	for i := 0; i < 10; i++ {
+		out := filepath.Join(t.TempDir(), "out")
+		m, err := New(Options{Source: src, OutputDir: out, MaxMediaSize: 10, OversizeMedia: OversizePlaceholder})
+		if err != nil { t.Fatal(err) }
+		if _, err := m.Run(context.Background()); err != nil { t.Fatal(err) }
+		got, err := os.ReadFile(filepath.Join(out, "index.html"))
+		if err != nil { t.Fatal(err) }
+		if string(got) != want { t.Fatalf("run %d:\n got %s\nwant %s", i, got, want) }
+	}
+}
+
+// findBySrc: src 속성이 같은 요소를 찾습니다. (빈 문자열이면 <body>)
+func findBySrc(n *html.Node, src string) *html.Node {
+	if n.Type == html.ElementNode && ((src == "" && n.Data == "body") || (src != "" && getAttr(n, "src") == src)) { return n }
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findBySrc(c, src); found != nil { return found }
+	}
+	return nil
+}
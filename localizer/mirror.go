@@ -0,0 +1,409 @@
+// Package localizer 는 정적 웹사이트(로컬 폴더) 또는 원격 웹사이트(URL)의 리소스를
+// 재귀적으로 수집하여 오프라인으로 열람 가능한 폴더로 미러링하는 엔진입니다.
+//
+// 모든 작업 상태는 Mirror 인스턴스에 보관되므로, 한 프로세스 안에서 여러 미러를
+// 동시에 실행해도 서로 간섭하지 않습니다.
+package localizer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [공개 설정 및 결과 타입]
+// ==========================================
+
+const (
+	AssetDir = "assets" // JS, CSS, 이미지 저장 하위 폴더명
+	FontDir  = "fonts"  // 폰트 파일 저장 하위 폴더명
+)
+
+// Options: Mirror 생성에 필요한 설정값입니다.
+type Options struct {
+	Source    string     // 입력 경로 (로컬 폴더 경로 또는 http(s):// URL)
+	OutputDir string     // 결과물이 저장될 최종 루트 폴더
+	Log       io.Writer  // 진행 상황 출력 대상 (nil이면 출력하지 않음)
+	Workers   int        // 동시에 다운로드할 리소스 수 (0 이하이면 DefaultWorkers)
+	Naming    Naming     // 저장 파일명 전략 (빈 값이면 NamingPath)
+	Format    Format     // 출력 형식 (빈 값이면 FormatFolder)
+	Srcset    SrcsetMode // srcset 후보 다운로드 범위 (빈 값이면 SrcsetAll)
+	Archive   string     // 모든 HTTP 요청/응답을 기록할 아카이브 경로 (.warc, .warc.gz, .wacz / 빈 값이면 기록 안 함)
+
+	// <a href> 링크 추적 (Depth가 0이면 링크를 따라가지 않음)
+	Depth        int      // 시작 페이지로부터 따라갈 최대 링크 깊이
+	Scope        Scope    // 링크 추적 범위 (빈 값이면 ScopeHost)
+	AllowedHosts []string // ScopeHosts 범위에서 추가로 허용할 호스트 목록
+
+	Timeouts Timeouts // 단계별 시간 제한 (0 필드는 DefaultTimeouts 사용)
+	Wait     Wait     // 렌더링 완료 판단 방식 (비어 있으면 Settle 고정 대기)
+	Scroll   Scroll   // 캡처 전 자동 스크롤 (Enabled가 false이면 스크롤하지 않음)
+
+	// 미디어(video, audio, source, object, embed) 크기 제한
+	MaxMediaSize  int64          // 이 크기(바이트)를 넘는 미디어는 받지 않음 (0이면 무제한)
+	OversizeMedia OversizePolicy // 제한을 넘는 미디어 처리 방식 (빈 값이면 OversizeSkip)
+
+	// Integrity: 로컬로 바꾼 리소스의 integrity 속성 처리 방식 (빈 값이면 IntegrityRecompute)
+	// crossorigin 속성과 CSP <meta>는 설정과 관계없이 로컬 사본에서 제거됩니다.
+	Integrity IntegrityMode
+
+	// MaxTabs: 동시에 처리할 페이지 수 (원격 모드에서는 공유 브라우저에 동시에 열리는 탭 수, 0 이하이면 DefaultMaxTabs)
+	MaxTabs int
+
+	// DisableCapture: true이면 브라우저가 받은 응답을 재사용하지 않고 모든 리소스를 HTTP로 다시 받습니다.
+	DisableCapture bool
+
+	// Output: 출력 폴더가 이미 있을 때의 처리 방식 (빈 값이면 OutputFail: 비어 있지 않은 폴더가 있으면 ErrOutputExists)
+	Output OutputPolicy
+
+	// Report: 페이지와 리소스 참조별 처리 결과를 기록할 JSON 파일 경로 (빈 값이면 기록 안 함)
+	Report string
+
+	// 요청 헤더와 인증 정보 (HTTP 리소스 요청과 Chrome 렌더링에 똑같이 적용)
+	// 헤더와 Authorization은 다른 사이트로 새지 않도록 크롤링 범위의 호스트로 가는 요청에만 붙입니다. (User-Agent 제외)
+	Headers     http.Header    // 추가 요청 헤더 (User-Agent는 모든 요청과 브라우저에 적용)
+	Cookies     []*http.Cookie // 초기 쿠키 (Domain이 비어 있으면 시작 URL의 호스트, "."으로 시작하면 하위 도메인 포함)
+	BasicAuth   string         // HTTP Basic 인증 정보 ("사용자:비밀번호")
+	BearerToken string         // Authorization: Bearer 토큰 (BasicAuth와 함께 사용할 수 없음)
+
+	// Steps: 원격 페이지를 렌더링한 뒤 HTML을 가져오기 전에 수행할 조작 (로그인, 쿠키 배너 닫기, 탭 클릭 등, LoadSteps로 파일에서 읽기)
+	Steps []Step
+}
+
+// DefaultWorkers: Options.Workers 미지정 시 사용되는 동시 다운로드 수
+const DefaultWorkers = 4
+
+// Result: Run 실행 결과 요약입니다.
+type Result struct {
+	Files int   // 저장된 파일 수
+	Bytes int64 // 저장된 전체 바이트 수
+
+	Failed int // 받지 못해 원본 URL로 남은 리소스 참조 수 (크기 제한으로 생략한 참조 제외)
+}
+
+// 고루틴 결과를 전달받기 위한 구조체
+type renderResult struct {
+	Data []byte
+	Err  error
+}
+
+// Mirror: 하나의 미러링 작업과 그 상태를 보관합니다.
+type Mirror struct {
+	opts Options
+	log  io.Writer
+
+	rootDir   string // 작업의 기준이 되는 루트 경로 (로컬 폴더 경로 또는 웹 Base URL)
+	startFile string // 최초 진입점이 되는 파일명 (예: index.html)
+	remote    bool   // 원격 URL 크롤링 모드 여부
+	recording bool   // 기록 프록시 모드 (브라우저 렌더링 대신 기록된 HTML 사용)
+
+	httpClient *http.Client         // 개별 리소스 요청용 HTTP 클라이언트 (쿠키 저장소는 브라우저와 동기화)
+	browser    *browserPool         // 실행 동안 공유하는 Chrome 인스턴스
+	archive    *warcWriter          // WARC/WACZ 기록기 (Options.Archive 미지정 시 nil)
+	renderChan chan renderResult    // 메인 페이지 렌더링 결과를 전달받는 채널
+	report     *runReport           // 페이지/리소스 참조별 처리 결과
+	journal    *pageJournal         // 완료된 페이지 기록 (이어서 진행용, 아카이브 전용 모드에서는 nil)
+	resumed    map[string][]pageRef // 이전 실행에서 완료된 페이지 -> 발견한 링크 (OutputResume, 준비 후 읽기 전용)
+
+	userAgent   string      // HTTP 요청과 브라우저가 공유하는 User-Agent
+	siteHeaders http.Header // 사이트 호스트로 가는 요청에 붙일 헤더 (Authorization 포함)
+
+	sem   chan struct{} // 동시 다운로드 수를 제한하는 세마포어 (워커 풀)
+	logMu sync.Mutex    // 진행 로그 출력 직렬화
+
+	startOnce sync.Once // 전체 작업 시간 제한의 기준 시각 설정
+	startedAt time.Time
+
+	// 중복 처리 방지 및 방문 기록 맵 (mu로 보호)
+	mu             sync.Mutex
+	processedFiles map[string]string
+	inflight       map[string]*inflightCall     // 다운로드 진행 중인 URL
+	claimedPaths   map[string]string            // 저장 경로 -> 사용 중인 URL (이름 충돌 방지)
+	visitedHTMLs   map[string]bool              // 처리 대상으로 등록된 페이지 (저장 경로 기준)
+	pageLinks      map[string][]pageLinkRef     // 저장된 페이지 -> 로컬 경로로 바꾼 페이지 링크 (깨진 링크 복원용)
+	pageQueue      []pageRef                    // <a href>로 발견되어 처리 대기 중인 페이지
+	captured       map[string]*capturedResponse // 브라우저 렌더링 중 캡처된 응답 (URL 기준)
+	documents      []*capturedResponse          // 기록 프록시 모드: 방문 순서대로 기록된 HTML 문서
+	stored         map[string]*storedEntry      // 저장 경로 -> 처리 완료 신호 (단일 파일 모드에서는 내장할 내용 포함)
+
+	// 통계 집계용 변수 (mu로 보호)
+	totalFiles int
+	totalBytes int64
+}
+
+// New: Options를 분석하여 새 Mirror를 생성합니다. (URL vs Local 모드 결정)
+func New(opts Options) (*Mirror, error) {
+	if opts.Source == "" { return nil, fmt.Errorf("입력 경로가 비어 있습니다") }
+	if opts.OutputDir == "" { return nil, fmt.Errorf("출력 폴더가 비어 있습니다") }
+	naming, ok := ParseNaming(string(opts.Naming))
+	if !ok { return nil, fmt.Errorf("알 수 없는 파일명 전략입니다: %s", opts.Naming) }
+	opts.Naming = naming
+	format, ok := ParseFormat(string(opts.Format))
+	if !ok { return nil, fmt.Errorf("알 수 없는 출력 형식입니다: %s", opts.Format) }
+	opts.Format = format
+	srcset, ok := ParseSrcsetMode(string(opts.Srcset))
+	if !ok { return nil, fmt.Errorf("알 수 없는 srcset 전략입니다: %s", opts.Srcset) }
+	opts.Srcset = srcset
+	oversize, ok := ParseOversizePolicy(string(opts.OversizeMedia))
+	if !ok { return nil, fmt.Errorf("알 수 없는 미디어 크기 초과 처리 방식입니다: %s", opts.OversizeMedia) }
+	opts.OversizeMedia = oversize
+	integrity, ok := ParseIntegrityMode(string(opts.Integrity))
+	if !ok { return nil, fmt.Errorf("알 수 없는 integrity 처리 방식입니다: %s", opts.Integrity) }
+	opts.Integrity = integrity
+	output, ok := ParseOutputPolicy(string(opts.Output))
+	if !ok { return nil, fmt.Errorf("알 수 없는 출력 폴더 정책입니다: %s", opts.Output) }
+	opts.Output = output
+	if format == FormatArchive && opts.Archive == "" { return nil, fmt.Errorf("archive 출력 형식에는 아카이브 경로가 필요합니다") }
+	scope, ok := ParseScope(string(opts.Scope))
+	if !ok { return nil, fmt.Errorf("알 수 없는 크롤링 범위입니다: %s", opts.Scope) }
+	opts.Scope = scope
+	opts.Timeouts = opts.Timeouts.normalize()
+	wait, err := opts.Wait.validate()
+	if err != nil { return nil, err }
+	opts.Wait = wait
+	opts.Scroll = opts.Scroll.normalize()
+	for i, step := range opts.Steps {
+		if err := step.validate(); err != nil { return nil, fmt.Errorf("단계 %d: %w", i+1, err) }
+	}
+
+	workers := opts.Workers
+	if workers <= 0 { workers = DefaultWorkers }
+
+	m := &Mirror{
+		opts:           opts,
+		log:            opts.Log,
+		httpClient:     &http.Client{Timeout: opts.Timeouts.Request},
+		sem:            make(chan struct{}, workers),
+		processedFiles: make(map[string]string),
+		inflight:       make(map[string]*inflightCall),
+		claimedPaths:   make(map[string]string),
+		visitedHTMLs:   make(map[string]bool),
+		pageLinks:      make(map[string][]pageLinkRef),
+		captured:       make(map[string]*capturedResponse),
+		stored:         make(map[string]*storedEntry),
+		report:         newRunReport(),
+	}
+	if m.log == nil { m.log = io.Discard }
+	if opts.Archive != "" { m.archive = newWarcWriter(opts.Archive) }
+
+	if strings.HasPrefix(opts.Source, "http://") || strings.HasPrefix(opts.Source, "https://") {
+		if err := m.setupRemoteMode(opts.Source); err != nil { return nil, err }
+	} else {
+		m.setupLocalMode(opts.Source)
+	}
+	if err := m.setupCredentials(); err != nil { return nil, err }
+	m.browser = newBrowserPool(opts.MaxTabs, m.userAgent, chromedp.ActionFunc(m.setBrowserCookies))
+	return m, nil
+}
+
+// RootDir: 작업의 기준 경로 (로컬 폴더 또는 Base URL)를 반환합니다.
+func (m *Mirror) RootDir() string { return m.rootDir }
+
+// StartFile: 최초 진입점 파일명을 반환합니다.
+func (m *Mirror) StartFile() string { return m.startFile }
+
+// IsRemote: 원격 URL 모드 여부를 반환합니다.
+func (m *Mirror) IsRemote() bool { return m.remote }
+
+// OutputDir: 결과물이 저장될 루트 폴더를 반환합니다.
+func (m *Mirror) OutputDir() string { return m.opts.OutputDir }
+
+// Run: 출력 폴더를 준비하고 시작 파일부터 미러링을 수행합니다.
+// Depth가 지정되면 발견된 <a href> 페이지를 깊이별로(너비 우선) 이어서 처리하며,
+// 같은 깊이의 페이지는 MaxTabs 개까지 동시에 처리합니다.
+// 오류가 발생해도 그때까지의 통계를 담은 Result를 함께 반환합니다.
+// 시간 제한을 초과하면 어떤 제한을 넘었는지 담은 *TimeoutError를 반환합니다.
+// 종료 시 공유 브라우저를 닫고, Options.Archive 가 지정되었으면 (중단된 경우에도) 아카이브를 마무리합니다.
+// Options.Report 가 지정되었으면 (실패한 경우에도) 실행 보고서를 저장합니다.
+func (m *Mirror) Run(ctx context.Context) (*Result, error) {
+	defer m.Close()
+	ctx, cancel := m.withBudget(ctx)
+	defer cancel()
+
+	err := m.run(ctx)
+	// 저장되지 않은 페이지(실패, 중단)를 가리키도록 바꾼 링크는 원래 주소로 되돌림
+	m.restoreMissingPageLinks()
+	m.journal.finish(err == nil)
+	if archiveErr := m.archive.finish(); err == nil { err = archiveErr }
+	res := m.result()
+	if m.opts.Report != "" {
+		if reportErr := writeReport(m.opts.Report, m.report.build(m, res, err)); err == nil && reportErr != nil {
+			err = fmt.Errorf("실행 보고서 저장 실패: %w", reportErr)
+		}
+	}
+	return res, err
+}
+
+func (m *Mirror) run(ctx context.Context) error {
+	if err := m.prepareOutput(); err != nil { return err }
+	if m.recording { return m.runRecorded(ctx) }
+
+	start := m.startPage()
+	m.schedulePage(start)
+	if err := m.processHTMLFile(ctx, start); err != nil { return timeoutCause(ctx, err) }
+
+	for {
+		level := m.drainPages()
+		if len(level) == 0 { break }
+		m.processPages(ctx, level)
+		if ctx.Err() != nil { return timeoutCause(ctx, ctx.Err()) }
+	}
+	return nil
+}
+
+// processPages: 같은 깊이의 페이지들을 최대 MaxTabs 개씩 동시에 처리합니다.
+func (m *Mirror) processPages(ctx context.Context, pages []pageRef) {
+	limit := make(chan struct{}, cap(m.browser.tabs))
+	var wg sync.WaitGroup
+	for _, page := range pages {
+		limit <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-limit; wg.Done() }()
+			if err := m.processHTMLFile(ctx, page); err != nil && ctx.Err() == nil {
+				m.logf(" ⚠️  페이지 처리 실패 (%s): %v\n", page.Source, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Close: 실행 중 사용한 브라우저를 종료합니다. Run은 종료 시 자동으로 호출하며,
+// Prefetch 후 Run을 호출하지 않고 끝내는 경우에만 직접 호출하면 됩니다. 여러 번 호출해도 안전합니다.
+func (m *Mirror) Close() {
+	m.browser.close()
+	m.archive.abort()
+}
+
+func (m *Mirror) result() *Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &Result{Files: m.totalFiles, Bytes: m.totalBytes, Failed: m.report.failed()}
+}
+
+// ==========================================
+// [설정 및 유효성 검사 함수들]
+// ==========================================
+
+// setupRemoteMode: 원격 URL을 분석하여 rootDir(Base URL)과 startFile을 설정합니다.
+func (m *Mirror) setupRemoteMode(inputURL string) error {
+	m.remote = true
+	u, err := url.Parse(inputURL)
+	if err != nil { return fmt.Errorf("잘못된 URL입니다: %w", err) }
+	ext := filepath.Ext(u.Path)
+	// URL이 구체적인 파일을 가리키는지 확인
+	isExplicitFile := ext != "" || (!strings.HasSuffix(u.Path, "/") && u.Path != "" && u.Path != "/")
+
+	if isExplicitFile {
+		m.startFile = path.Base(u.Path)
+		u.Path = path.Dir(u.Path)
+	} else {
+		m.startFile = "index.html"
+	}
+
+	// Base URL 정규화
+	if u.Path == "." || u.Path == "" { u.Path = "/" }
+	if !strings.HasSuffix(u.Path, "/") { u.Path += "/" }
+	m.rootDir = u.String()
+	return nil
+}
+
+// setupLocalMode: 로컬 파일 경로를 기준으로 설정을 초기화합니다.
+func (m *Mirror) setupLocalMode(inputPath string) {
+	m.remote = false
+	m.rootDir = inputPath
+	m.startFile = "index.html"
+}
+
+// Prefetch: 원격 모드일 경우 메인 페이지 렌더링을 별도 고루틴에서 즉시 시작합니다. (프리페칭)
+// Run 이전에 호출하면, 사용자 확인 등을 기다리는 동안 렌더링이 진행됩니다.
+func (m *Mirror) Prefetch(ctx context.Context) {
+	if !m.remote || m.renderChan != nil { return }
+	m.renderChan = make(chan renderResult, 1)
+	targetURL := m.startPage().Source
+
+	m.logf("-> 입력 URL 렌더링 시작\n")
+
+	ctx, cancel := m.withBudget(ctx)
+	go func() {
+		defer cancel()
+		// fetchRenderedHTML 내부에서 페이지별 렌더링 제한(Timeouts.Render)을 별도로 사용함
+		data, err := m.fetchRenderedHTML(ctx, targetURL)
+
+		// 메인 스레드가 이미 종료되었을 경우를 대비한 select
+		select {
+		case m.renderChan <- renderResult{Data: data, Err: err}:
+		case <-ctx.Done():
+		}
+		close(m.renderChan)
+	}()
+}
+
+// Validate: 입력된 경로가 실제로 접근 가능한지 사전 검사합니다.
+func (m *Mirror) Validate() error {
+	if m.remote {
+		checkURL := m.rootDir
+		if m.startFile != "index.html" {
+			u, _ := url.Parse(m.rootDir)
+			rel, _ := url.Parse(m.startFile)
+			checkURL = u.ResolveReference(rel).String()
+		}
+		// 가벼운 HTTP Request로 연결 확인
+		req, err := m.newRequest(context.Background(), "GET", checkURL)
+		if err != nil { return err }
+
+		resp, err := m.httpClient.Do(req)
+		if err != nil { return fmt.Errorf("원격 서버 접속 불가 (%w)", err) }
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 { return fmt.Errorf("원격 리소스 없음 (HTTP %d)", resp.StatusCode) }
+	} else {
+		checkPath := filepath.Join(m.rootDir, m.startFile)
+		if _, err := os.Stat(checkPath); os.IsNotExist(err) {
+			return fmt.Errorf("입력 파일을 찾을 수 없음 (%s)", checkPath)
+		}
+	}
+	return nil
+}
+
+// prepareOutput: Options.Output 정책을 적용한 뒤 결과물 저장에 필요한 하위 폴더(assets, fonts)를 생성하고 실행 기록을 엽니다.
+// 단일 파일 모드에서는 출력 폴더만 생성하고, 아카이브 전용 모드에서는 아무 것도 만들지 않습니다.
+func (m *Mirror) prepareOutput() error {
+	if m.opts.Format == FormatArchive { return nil }
+	if err := m.applyOutputPolicy(); err != nil { return err }
+	dirs := []string{m.opts.OutputDir}
+	if !m.singleFile() {
+		dirs = []string{
+			filepath.Join(m.opts.OutputDir, AssetDir),
+			filepath.Join(m.opts.OutputDir, FontDir),
+		}
+	}
+	for _, d := range dirs {
+		if err := os.MkdirAll(d, 0755); err != nil { return fmt.Errorf("폴더 생성 실패: %w", err) }
+	}
+	return m.openJournal()
+}
+
+func (m *Mirror) updateStats(size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totalFiles++
+	m.totalBytes += size
+}
+
+// logf: 여러 고루틴의 진행 로그가 섞이지 않도록 한 줄씩 출력합니다.
+func (m *Mirror) logf(format string, args ...any) {
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+	fmt.Fprintf(m.log, format, args...)
+}
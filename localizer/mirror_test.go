@@ -0,0 +1,205 @@
+package localizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// mirrorFixture: 로컬 모드 Run 테스트용 입력 폴더 (logo.png는 여러 페이지/속성에서 참조됨)
+var mirrorFixture = map[string]string{
+	"index.html": `<html><head><meta http-equiv="Content-Security-Policy" content="default-src 'none'">` +
+		`<link rel="stylesheet" href="style.css" integrity="sha256-AAAA" crossorigin="anonymous">` +
+		`<script src="app.js" integrity="sha256-BBBB" crossorigin="anonymous"></script></head><body>` +
+		`<img src="img/logo.png"><img src="img/logo.png" srcset="img/logo.png 1x, img/logo2x.png 2x"><img src="missing.png">` +
+		`<a href="sub/page.html">next</a></body></html>`,
+	"sub/page.html":  `<html><head><link rel="stylesheet" href="../style.css"></head><body><img src="../img/logo.png"><a href="../index.html">home</a></body></html>`,
+	"style.css":      `body{background:url(img/bg.png)}@font-face{font-family:x;src:url(font.woff2)}`,
+	"app.js":         `console.log(1)`,
+	"img/logo.png":   "png",
+	"img/logo2x.png": "png2x",
+	"img/bg.png":     "bg",
+	"font.woff2":     "woff",
+}
+
+var mirrorFolderFiles = []string{
+	"assets/app.js", "assets/img/bg.png", "assets/img/logo.png", "assets/img/logo2x.png", "assets/style.css",
+	"fonts/font.woff2", "index.html", "sub/page.html",
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { t.Fatal(err) }
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil { t.Fatal(err) }
+	}
+}
+
+// listFiles: 폴더 안의 모든 파일을 슬래시 구분 상대 경로로 정렬해 반환합니다.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() { return err }
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil { t.Fatal(err) }
+	sort.Strings(files)
+	return files
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil { t.Fatal(err) }
+	return string(data)
+}
+
+func sriOf(t *testing.T, path string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(readFile(t, path)))
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestRunLocal(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		setup   func(t *testing.T, src, out string) // 실행 전 출력 폴더 준비 (기존 폴더 정책 확인용)
+		wantErr error
+		files   []string
+		check   func(t *testing.T, out string, res *Result, rep *Report)
+	}{
+		{
+			name:  "folder",
+			opts:  Options{Depth: 1, Workers: 8},
+			files: mirrorFolderFiles,
+			check: func(t *testing.T, out string, res *Result, rep *Report) {
+				// 같은 리소스를 여러 곳에서 동시에 참조해도 한 번만 저장
+				if res.Files != len(mirrorFolderFiles) || res.Failed != 1 { t.Errorf("result = %+v", res) }
+				index := readFile(t, filepath.Join(out, "index.html"))
+				for _, want := range []string{
+					`<link rel="stylesheet" href="assets/style.css" integrity="` + sriOf(t, filepath.Join(out, "assets/style.css")) + `"/>`,
+					`<script src="assets/app.js" integrity="` + sriOf(t, filepath.Join(out, "assets/app.js")) + `"></script>`,
+					`<img src="assets/img/logo.png"/><img src="assets/img/logo.png" srcset="assets/img/logo.png 1x, assets/img/logo2x.png 2x"/>`,
+					`<img src="missing.png"/>`, `<a href="sub/page.html">`,
+				} {
+					if !strings.Contains(index, want) { t.Errorf("index.html lacks %s:\n%s", want, index) }
+				}
+				for _, gone := range []string{"crossorigin", "Content-Security-Policy"} {
+					if strings.Contains(index, gone) { t.Errorf("index.html still has %s:\n%s", gone, index) }
+				}
+				if got := readFile(t, filepath.Join(out, "sub/page.html")); !strings.Contains(got, `href="../assets/style.css"`) || !strings.Contains(got, `src="../assets/img/logo.png"`) {
+					t.Errorf("sub/page.html:\n%s", got)
+				}
+				if got, want := readFile(t, filepath.Join(out, "assets/style.css")), `body{background:url('img/bg.png')}@font-face{font-family:x;src:url('../fonts/font.woff2')}`; got != want {
+					t.Errorf("style.css = %s, want %s", got, want)
+				}
+
+				want := ReportSummary{Files: len(mirrorFolderFiles), Bytes: res.Bytes, Pages: 2, References: 11, Saved: 10, Failed: 1}
+				if rep.Summary != want { t.Errorf("report summary = %+v, want %+v", rep.Summary, want) }
+				for _, p := range rep.Pages {
+					if p.Outcome != OutcomeSaved { t.Errorf("page %s: %s %s", p.Source, p.Outcome, p.Error) }
+				}
+				for _, r := range rep.Resources {
+					if (r.Outcome == OutcomeFailed) != (r.Reference == "missing.png") { t.Errorf("resource %s: %s %s", r.Reference, r.Outcome, r.Error) }
+					if r.Reference == "font.woff2" && (r.Page != "style.css" || r.SavedPath != "fonts/font.woff2") { t.Errorf("font reference = %+v", r) }
+				}
+			},
+		},
+		{
+			name:  "integrity drop",
+			opts:  Options{Integrity: IntegrityDrop},
+			files: []string{"assets/app.js", "assets/img/bg.png", "assets/img/logo.png", "assets/img/logo2x.png", "assets/style.css", "fonts/font.woff2", "index.html"},
+			check: func(t *testing.T, out string, res *Result, rep *Report) {
+				if index := readFile(t, filepath.Join(out, "index.html")); strings.Contains(index, "integrity") { t.Errorf("index.html keeps integrity:\n%s", index) }
+			},
+		},
+		{
+			name:  "single file",
+			opts:  Options{Format: FormatSingle, Depth: 1},
+			files: []string{"index.html", "sub/page.html"},
+			check: func(t *testing.T, out string, res *Result, rep *Report) {
+				index := readFile(t, filepath.Join(out, "index.html"))
+				for _, want := range []string{
+					"<style>body{background:url('data:image/png;base64,Ymc=')}@font-face{font-family:x;src:url('data:font/woff2;base64,d29mZg==')}</style>",
+					"<script>console.log(1)</script>", `<img src="data:image/png;base64,cG5n"/>`, "data:image/png;base64,cG5nMng= 2x", `<img src="missing.png"/>`,
+				} {
+					if !strings.Contains(index, want) { t.Errorf("index.html lacks %s:\n%s", want, index) }
+				}
+				if strings.Contains(index, "assets/") || strings.Contains(index, "integrity") { t.Errorf("index.html refers to separate files:\n%s", index) }
+			},
+		},
+		{
+			name:    "existing output fails by default",
+			setup:   func(t *testing.T, src, out string) { writeFiles(t, out, map[string]string{"old.txt": "old"}) },
+			wantErr: ErrOutputExists,
+			files:   []string{"old.txt"},
+		},
+		{
+			name:  "overwrite",
+			opts:  Options{Output: OutputOverwrite},
+			setup: func(t *testing.T, src, out string) { writeFiles(t, out, map[string]string{"old.txt": "old"}) },
+			files: []string{"assets/app.js", "assets/img/bg.png", "assets/img/logo.png", "assets/img/logo2x.png", "assets/style.css", "fonts/font.woff2", "index.html"},
+		},
+		{
+			name:  "merge",
+			opts:  Options{Output: OutputMerge},
+			setup: func(t *testing.T, src, out string) { writeFiles(t, out, map[string]string{"old.txt": "old"}) },
+			files: []string{"assets/app.js", "assets/img/bg.png", "assets/img/logo.png", "assets/img/logo2x.png", "assets/style.css", "fonts/font.woff2", "index.html", "old.txt"},
+		},
+		{
+			name: "resume",
+			opts: Options{Output: OutputResume, Depth: 1},
+			setup: func(t *testing.T, src, out string) {
+				// 시작 페이지만 저장하고 중단된 이전 실행
+				journal := `{"run":"` + src + `"}` + "\n" +
+					`{"page":"index.html","source":"index.html","links":[{"source":"sub/page.html","out_rel":"sub/page.html","depth":1}]}` + "\n" +
+					`{"page":"sub/pa` // 강제 종료로 잘린 줄
+				writeFiles(t, out, map[string]string{"index.html": "previous run", journalFile: journal})
+			},
+			files: []string{"assets/img/logo.png", "assets/style.css", "fonts/font.woff2", "assets/img/bg.png", "index.html", "sub/page.html"},
+			check: func(t *testing.T, out string, res *Result, rep *Report) {
+				if got := readFile(t, filepath.Join(out, "index.html")); got != "previous run" { t.Errorf("completed page was processed again: %s", got) }
+				if got := readFile(t, filepath.Join(out, "sub/page.html")); !strings.Contains(got, `src="../assets/img/logo.png"`) { t.Errorf("sub/page.html:\n%s", got) }
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := t.TempDir()
+			writeFiles(t, src, mirrorFixture)
+			out := filepath.Join(t.TempDir(), "out")
+			if tt.setup != nil { tt.setup(t, src, out) }
+
+			opts := tt.opts
+			opts.Source, opts.OutputDir = src, out
+			opts.Report = filepath.Join(t.TempDir(), "report.json")
+			m, err := New(opts)
+			if err != nil { t.Fatal(err) }
+			res, err := m.Run(context.Background())
+			if !errors.Is(err, tt.wantErr) { t.Fatalf("Run error = %v, want %v", err, tt.wantErr) }
+
+			sort.Strings(tt.files)
+			if got := listFiles(t, out); !reflect.DeepEqual(got, tt.files) { t.Errorf("output files:\n got %q\nwant %q", got, tt.files) }
+
+			var rep Report
+			if err := json.Unmarshal([]byte(readFile(t, opts.Report)), &rep); err != nil { t.Fatal(err) }
+			if rep.Source != src || rep.OutputDir != out { t.Errorf("report source/output = %s, %s", rep.Source, rep.OutputDir) }
+			if (rep.Error != "") != (tt.wantErr != nil) { t.Errorf("report error = %q, want %v", rep.Error, tt.wantErr) }
+			if tt.check != nil { tt.check(t, out, res, &rep) }
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package localizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ==========================================
+// [저장 파일명 결정 (Naming Strategy)]
+// ==========================================
+
+// Naming: 리소스를 assets/, fonts/ 아래에 어떤 이름으로 저장할지 결정하는 전략입니다.
+type Naming string
+
+const (
+	// NamingPath: 원본 호스트/경로 구조를 그대로 유지합니다. (예: assets/cdn.example.com/a/logo.png)
+	NamingPath Naming = "path"
+	// NamingHash: 파일명 뒤에 내용 해시를 붙여 평탄하게 저장합니다. (예: assets/logo.3f2a9c1d.png)
+	NamingHash Naming = "hash"
+)
+
+// ParseNaming: 문자열을 Naming으로 변환합니다. 빈 문자열은 NamingPath로 취급합니다.
+func ParseNaming(s string) (Naming, bool) {
+	switch Naming(strings.ToLower(strings.TrimSpace(s))) {
+	case "", NamingPath:
+		return NamingPath, true
+	case NamingHash:
+		return NamingHash, true
+	}
+	return "", false
+}
+
+// resourceFileName: URL(또는 로컬 경로)의 마지막 경로 요소를 쿼리 없이 반환합니다.
+func resourceFileName(u *url.URL, targetURL string, isRemote bool) string {
+	var fileName string
+	if isRemote { fileName = path.Base(u.Path) } else { fileName = filepath.Base(stripQuery(targetURL)) }
+	if fileName == "." || fileName == "/" || fileName == "" {
+		// 경로 없는 URL (예: https://cdn.tailwindcss.com) 은 스크립트로 간주
+		if isRemote && u.Host != "" { fileName = "index.js" } else { fileName = "resource.bin" }
+	}
+	return sanitizeName(fileName)
+}
+
+// hierarchyPath: NamingPath 전략의 저장 경로(subDir 기준)를 계산합니다.
+// 원격은 "호스트/경로", 로컬은 루트 폴더 기준 상대 경로를 사용하며, 쿼리 문자열이 있으면
+// 파일명에 쿼리 해시를 붙여 style.css?v=1 과 style.css?v=2 가 구분되도록 합니다.
+func (m *Mirror) hierarchyPath(u *url.URL, targetURL string, isRemote bool, fileName string) string {
+	var dir, query string
+	if isRemote {
+		dir = path.Join(sanitizeName(u.Host), path.Dir(path.Clean("/"+u.Path)))
+		query = u.RawQuery
+	} else {
+		localPath := stripQuery(targetURL)
+		if i := strings.IndexByte(targetURL, '?'); i != -1 { query = targetURL[i+1:] }
+		rel, err := filepath.Rel(m.rootDir, localPath)
+		if err != nil { rel = filepath.Base(localPath) }
+		dir = filepath.ToSlash(filepath.Dir(rel))
+	}
+
+	// 상위 폴더 참조(..)나 금지 문자가 출력 폴더 밖으로 벗어나지 않도록 정리
+	var segments []string
+	for _, seg := range strings.Split(dir, "/") {
+		switch seg {
+		case "", ".":
+			continue
+		case "..":
+			seg = "__"
+		}
+		segments = append(segments, sanitizeName(seg))
+	}
+
+	if query != "" { fileName = addNameSuffix(fileName, shortHash([]byte(query))) }
+	return filepath.Join(append(segments, fileName)...)
+}
+
+// hashedName: NamingHash 전략의 파일명 (내용 해시를 확장자 앞에 삽입)
+// 참조를 다시 쓰는 CSS/스크립트는 호출하는 쪽에서 URL을 함께 넣어 해시합니다.
+func hashedName(fileName string, data []byte) string {
+	return addNameSuffix(fileName, shortHash(data))
+}
+
+// claimPath: 저장 경로를 key(URL)에 할당합니다. 다른 URL이 이미 같은 경로를 사용 중이면
+// URL 해시를 붙인 새 경로를 할당합니다. 같은 key가 다시 요청하면 shared=true를 반환합니다.
+// key가 빈 문자열이면 경로 자체를 key로 사용합니다. (내용 해시 이름처럼 경로 공유가 정상인 경우)
+func (m *Mirror) claimPath(saveRelPath string, key string) (claimed string, shared bool) {
+	if key == "" { key = saveRelPath }
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		owner, ok := m.claimedPaths[saveRelPath]
+		if !ok {
+			m.claimedPaths[saveRelPath] = key
+			return saveRelPath, false
+		}
+		if owner == key { return saveRelPath, true }
+		saveRelPath = addNameSuffix(saveRelPath, shortHash([]byte(key)))
+	}
+}
+
+// addNameSuffix: "name.ext" -> "name.<suffix>.ext"
+func addNameSuffix(fileName string, suffix string) string {
+	ext := path.Ext(fileName)
+	return strings.TrimSuffix(fileName, ext) + "." + suffix + ext
+}
+
+func shortHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
+
+// stripQuery: 로컬 경로에 붙은 ?쿼리 또는 #프래그먼트를 제거합니다.
+func stripQuery(p string) string {
+	if i := strings.IndexAny(p, "?#"); i != -1 { return p[:i] }
+	return p
+}
+
+// sanitizeName: 파일 시스템에서 사용할 수 없는 문자를 '_'로 치환합니다.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '*', '?', '"', '<', '>', '|', '\\':
+			return '_'
+		}
+		return r
+	}, name)
+}
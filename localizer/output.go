@@ -0,0 +1,199 @@
+package localizer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ==========================================
+// [출력 폴더 정책 및 이어서 진행 (resume)]
+// ==========================================
+
+// OutputPolicy: 출력 폴더가 이미 있을 때의 처리 방식입니다.
+type OutputPolicy string
+
+const (
+	OutputFail      OutputPolicy = "fail"      // 비어 있지 않은 폴더가 있으면 ErrOutputExists 반환
+	OutputOverwrite OutputPolicy = "overwrite" // 기존 폴더를 삭제하고 다시 생성
+	OutputMerge     OutputPolicy = "merge"     // 기존 파일 유지, 이미 받은 리소스는 다시 받지 않음 (페이지는 다시 처리)
+	OutputResume    OutputPolicy = "resume"    // merge + 중단된 이전 실행에서 완료된 페이지는 건너뜀
+)
+
+// ParseOutputPolicy: 문자열을 OutputPolicy로 변환합니다. 빈 문자열은 OutputFail로 취급합니다.
+// (정책을 지정하지 않은 호출자가 기존 폴더에 모르게 파일을 쓰지 않도록 함)
+func ParseOutputPolicy(s string) (OutputPolicy, bool) {
+	switch OutputPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", OutputFail:
+		return OutputFail, true
+	case OutputMerge:
+		return OutputMerge, true
+	case OutputOverwrite:
+		return OutputOverwrite, true
+	case OutputResume:
+		return OutputResume, true
+	}
+	return "", false
+}
+
+// ErrOutputExists: OutputFail 정책에서 출력 폴더가 이미 있을 때 반환됩니다. (errors.Is로 확인)
+var ErrOutputExists = errors.New("출력 폴더가 이미 존재합니다")
+
+// journalFile: 완료된 페이지를 기록하는 실행 기록 파일 (출력 폴더 안, 정상 종료 시 삭제)
+const journalFile = ".localizer-journal.jsonl"
+
+// OutputExists: 출력 폴더가 있고 비어 있지 않은지 확인합니다. (빈 폴더는 없는 것으로 취급)
+func OutputExists(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// applyOutputPolicy: Options.Output 에 따라 기존 출력 폴더를 확인/삭제하고,
+// resume이면 이전 실행 기록을 읽어 완료된 페이지 목록을 준비합니다.
+func (m *Mirror) applyOutputPolicy() error {
+	dir := m.opts.OutputDir
+	if !OutputExists(dir) { return nil }
+	absDir, _ := filepath.Abs(dir)
+
+	switch m.opts.Output {
+	case OutputFail:
+		return fmt.Errorf("%w: %s", ErrOutputExists, absDir)
+	case OutputOverwrite:
+		// 입력 폴더나 현재 작업 폴더를 감싸는 경로는 삭제하지 않음
+		cwd, _ := os.Getwd()
+		if !m.remote && containsPath(absDir, m.rootDir) { return fmt.Errorf("입력 폴더를 포함하는 출력 폴더는 삭제할 수 없습니다: %s", absDir) }
+		if containsPath(absDir, cwd) { return fmt.Errorf("현재 작업 폴더를 포함하는 출력 폴더는 삭제할 수 없습니다: %s", absDir) }
+		m.logf(" ♻️  기존 출력 폴더 삭제: %s\n", absDir)
+		if err := os.RemoveAll(dir); err != nil { return fmt.Errorf("기존 출력 폴더 삭제 실패: %w", err) }
+	case OutputResume:
+		done, err := m.readJournal()
+		if err != nil { return err }
+		if done == nil {
+			m.logf(" ℹ️  이어서 진행할 실행 기록이 없어 기존 폴더에 병합합니다.\n")
+			return nil
+		}
+		m.resumed = done
+		m.logf(" ⏯️  이전 실행에서 완료된 페이지 %d개를 건너뜁니다.\n", len(done))
+	}
+	return nil
+}
+
+// containsPath: parent가 child와 같거나 child의 상위 폴더인지 확인합니다.
+func containsPath(parent string, child string) bool {
+	if child == "" { return false }
+	absChild, err := filepath.Abs(child)
+	if err != nil { return false }
+	rel, err := filepath.Rel(parent, absChild)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ==========================================
+// [실행 기록 (journal)]
+// ==========================================
+
+// journalEntry: 실행 기록 한 줄. 첫 줄은 Run(입력 경로)만 담고, 이후 줄은 완료된 페이지입니다.
+type journalEntry struct {
+	Run    string        `json:"run,omitempty"`
+	Page   string        `json:"page,omitempty"` // 출력 폴더 기준 저장 경로
+	Source string        `json:"source,omitempty"`
+	Links  []journalLink `json:"links,omitempty"` // 페이지에서 발견한 <a href> 대상 (건너뛸 때 대기열에 다시 추가)
+}
+
+type journalLink struct {
+	Source string `json:"source"`
+	OutRel string `json:"out_rel"`
+	Depth  int    `json:"depth"`
+}
+
+// pageJournal: 완료된 페이지를 한 줄씩 덧붙여 기록합니다. (강제 종료되어도 그때까지의 기록이 남음)
+type pageJournal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// journalPath: 실행 기록 파일 경로
+func (m *Mirror) journalPath() string { return filepath.Join(m.opts.OutputDir, journalFile) }
+
+// readJournal: 같은 입력으로 실행한 이전 기록에서 완료된 페이지(저장 경로 -> 발견한 링크)를 읽습니다.
+// 기록이 없거나 다른 입력의 기록이면 nil을 반환합니다.
+func (m *Mirror) readJournal() (map[string][]pageRef, error) {
+	f, err := os.Open(m.journalPath())
+	if errors.Is(err, os.ErrNotExist) { return nil, nil }
+	if err != nil { return nil, err }
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() { return nil, scanner.Err() }
+	var header journalEntry
+	if json.Unmarshal(scanner.Bytes(), &header) != nil || header.Run != m.opts.Source {
+		m.logf(" ⚠️  다른 입력의 실행 기록이므로 사용하지 않습니다: %s\n", m.journalPath())
+		return nil, nil
+	}
+
+	done := make(map[string][]pageRef)
+	for scanner.Scan() {
+		var entry journalEntry
+		// 강제 종료로 마지막 줄이 잘렸을 수 있으므로 읽을 수 없는 줄은 무시
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Page == "" { continue }
+		links := make([]pageRef, 0, len(entry.Links))
+		for _, l := range entry.Links {
+			links = append(links, pageRef{Source: l.Source, OutRel: filepath.FromSlash(l.OutRel), Depth: l.Depth})
+		}
+		done[entry.Page] = links
+	}
+	return done, scanner.Err()
+}
+
+// openJournal: 실행 기록 파일을 엽니다. 이어서 진행하는 경우에는 기존 기록 뒤에 덧붙입니다.
+func (m *Mirror) openJournal() error {
+	path := m.journalPath()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if m.resumed != nil { flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND }
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil { return fmt.Errorf("실행 기록 파일 생성 실패: %w", err) }
+	m.journal = &pageJournal{path: path, file: f}
+	if m.resumed == nil { return m.journal.write(journalEntry{Run: m.opts.Source}) }
+	return nil
+}
+
+// completedPage: 이전 실행에서 완료된 페이지이면 그 페이지에서 발견한 링크를 반환합니다.
+func (m *Mirror) completedPage(page pageRef) ([]pageRef, bool) {
+	if m.resumed == nil { return nil, false }
+	links, ok := m.resumed[filepath.ToSlash(page.OutRel)]
+	return links, ok
+}
+
+// addPage: 저장을 마친 페이지를 기록합니다.
+func (j *pageJournal) addPage(page pageRef, links []pageRef) error {
+	if j == nil { return nil }
+	entry := journalEntry{Page: filepath.ToSlash(page.OutRel), Source: page.Source}
+	for _, l := range links {
+		entry.Links = append(entry.Links, journalLink{Source: l.Source, OutRel: filepath.ToSlash(l.OutRel), Depth: l.Depth})
+	}
+	return j.write(entry)
+}
+
+func (j *pageJournal) write(entry journalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil { return err }
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.file.Write(append(data, '\n'))
+	return err
+}
+
+// finish: 기록 파일을 닫습니다. 작업이 끝까지 완료되었으면 더 이어서 진행할 것이 없으므로 삭제합니다.
+func (j *pageJournal) finish(completed bool) {
+	if j == nil { return }
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.file.Close()
+	if completed { os.Remove(j.path) }
+}
@@ -0,0 +1,169 @@
+package localizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// [미러 결과 미리보기용 로컬 HTTP 서버]
+// ==========================================
+
+// DefaultPreviewAddr: PreviewOptions.Addr 미지정 시 사용하는 주소 (로컬에서만 접속 가능)
+const DefaultPreviewAddr = "127.0.0.1:8080"
+
+// PreviewOptions: 미리보기 서버 설정값입니다.
+type PreviewOptions struct {
+	Dir  string    // 제공할 폴더 (미러 결과물의 OutputDir)
+	Addr string    // 수신 주소 (빈 값이면 DefaultPreviewAddr, 포트 0이면 빈 포트 자동 선택)
+	SPA  bool      // 없는 경로의 페이지 요청에 루트 index.html을 제공 (클라이언트 라우팅 사이트용)
+	Log  io.Writer // 요청 로그 출력 대상 (nil이면 출력하지 않음)
+}
+
+// Preview: OutputDir을 HTTP로 제공하는 미리보기 서버입니다.
+// file:// 로는 동작하지 않는 ES 모듈, fetch(JSON), 서비스 워커를 확인할 수 있습니다.
+type Preview struct {
+	opts     PreviewOptions
+	log      io.Writer
+	logMu    sync.Mutex
+	listener net.Listener
+	files    http.Handler
+}
+
+// 시스템 MIME 테이블에 없거나 브라우저가 엄격하게 검사하는 형식 (모듈 스크립트, wasm 등)
+var previewTypes = map[string]string{
+	".html":        "text/html; charset=utf-8",
+	".htm":         "text/html; charset=utf-8",
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".css":         "text/css; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".webmanifest": "application/manifest+json",
+	".wasm":        "application/wasm",
+	".svg":         "image/svg+xml",
+	".webp":        "image/webp",
+	".avif":        "image/avif",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".otf":         "font/otf",
+	".eot":         "application/vnd.ms-fontobject",
+	".mp4":         "video/mp4",
+	".webm":        "video/webm",
+	".mp3":         "audio/mpeg",
+	".vtt":         "text/vtt; charset=utf-8",
+}
+
+// NewPreview: 폴더를 확인하고 수신 주소를 엽니다. (실제 요청 처리는 Run에서 시작)
+func NewPreview(opts PreviewOptions) (*Preview, error) {
+	info, err := os.Stat(opts.Dir)
+	if err != nil || !info.IsDir() { return nil, fmt.Errorf("제공할 폴더를 찾을 수 없습니다: %s", opts.Dir) }
+	if opts.Addr == "" { opts.Addr = DefaultPreviewAddr }
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil { return nil, fmt.Errorf("서버 주소를 열 수 없습니다 (%s): %w", opts.Addr, err) }
+
+	p := &Preview{opts: opts, log: opts.Log, listener: listener, files: http.FileServer(http.Dir(opts.Dir))}
+	if p.log == nil { p.log = io.Discard }
+	return p, nil
+}
+
+// URL: 브라우저에서 열 주소를 반환합니다. (0.0.0.0 등 모든 주소 수신이면 localhost로 표시)
+func (p *Preview) URL() string {
+	host, port, _ := net.SplitHostPort(p.listener.Addr().String())
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() { host = "localhost" }
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}
+
+// Dir: 제공 중인 폴더를 반환합니다.
+func (p *Preview) Dir() string { return p.opts.Dir }
+
+// Run: ctx가 취소될 때까지 요청을 처리합니다. 취소되면 진행 중인 요청을 마무리하고 nil을 반환합니다.
+func (p *Preview) Run(ctx context.Context) error {
+	server := &http.Server{Handler: p, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(p.listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) { return err }
+		return nil
+	}
+}
+
+// ServeHTTP: 정적 파일을 제공합니다. 결과물을 다시 만든 뒤 바로 확인할 수 있도록 캐시를 끕니다.
+func (p *Preview) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	method, reqPath := r.Method, r.URL.Path
+	defer func() { p.logf(" %d %s %s\n", rec.status, method, reqPath) }()
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(rec, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	urlPath := path.Clean("/" + r.URL.Path)
+	if p.opts.SPA && !p.exists(urlPath) && acceptsHTML(r) {
+		// SPA 대체: 존재하지 않는 페이지 경로(/users/42)는 루트 index.html이 처리
+		r = r.Clone(r.Context())
+		r.URL.Path = "/"
+		urlPath = "/index.html"
+	}
+
+	ext := strings.ToLower(path.Ext(urlPath))
+	if strings.HasSuffix(r.URL.Path, "/") { ext = ".html" }
+	contentType := previewTypes[ext]
+	if contentType == "" { contentType = mime.TypeByExtension(ext) }
+	if contentType != "" { rec.Header().Set("Content-Type", contentType) }
+	rec.Header().Set("Cache-Control", "no-store")
+	p.files.ServeHTTP(rec, r)
+}
+
+// exists: URL 경로에 해당하는 파일(디렉토리이면 index.html)이 있는지 확인합니다.
+func (p *Preview) exists(urlPath string) bool {
+	full := filepath.Join(p.opts.Dir, filepath.FromSlash(urlPath))
+	info, err := os.Stat(full)
+	if err != nil { return false }
+	if info.IsDir() { return fileExists(filepath.Join(full, "index.html")) }
+	return true
+}
+
+// acceptsHTML: 페이지 요청인지 확인합니다. (확장자가 없거나 Accept에 text/html 포함, 없는 이미지·스크립트는 404 유지)
+func acceptsHTML(r *http.Request) bool {
+	return path.Ext(r.URL.Path) == "" || strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func (p *Preview) logf(format string, args ...any) {
+	p.logMu.Lock()
+	defer p.logMu.Unlock()
+	fmt.Fprintf(p.log, format, args...)
+}
+
+// statusRecorder: 요청 로그에 표시할 응답 상태 코드를 기록합니다.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
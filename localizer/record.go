@@ -0,0 +1,375 @@
+package localizer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// ==========================================
+// [기록 프록시 (localizer record)]
+// ==========================================
+
+// DefaultRecordAddr: RecordOptions.Addr 미지정 시 사용하는 프록시 주소 (로컬에서만 접속 가능)
+const DefaultRecordAddr = "127.0.0.1:8081"
+
+// RecordOptions: 기록 프록시 설정값입니다.
+type RecordOptions struct {
+	Addr  string // 프록시 수신 주소 (빈 값이면 DefaultRecordAddr)
+	CADir string // HTTPS 가로채기용 로컬 CA 보관 폴더 (빈 값이면 DefaultCADir())
+}
+
+// DefaultCADir: 로컬 CA 인증서와 키를 보관하는 기본 폴더 (사용자 설정 폴더/localizer)
+func DefaultCADir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil { return ".localizer" }
+	return filepath.Join(dir, "localizer")
+}
+
+// Recorder: 브라우저가 프록시로 방문한 모든 응답을 기록하고, 종료 시 같은 처리 파이프라인으로 미러를 작성합니다.
+// 로그인 등 사람의 조작이 있어야 도달할 수 있는 페이지를 미러링할 때 사용합니다.
+type Recorder struct {
+	m         *Mirror
+	ca        *localCA
+	listener  net.Listener
+	transport *http.Transport
+
+	connMu sync.Mutex
+	conns  map[net.Conn]bool // HTTPS 터널 (종료 시 강제로 닫기 위해 추적)
+
+	responses atomic.Int64 // 기록한 응답 수
+}
+
+// NewRecorder: 로컬 CA를 준비하고 프록시 주소를 엽니다. (요청 기록은 Run에서 시작)
+// 미러는 시작 URL로 생성해야 하며, 시작 URL은 저장 경로와 Scope 판단의 기준이 됩니다.
+func (m *Mirror) NewRecorder(opts RecordOptions) (*Recorder, error) {
+	if !m.remote { return nil, fmt.Errorf("기록 모드에는 시작 URL(http:// 또는 https://)이 필요합니다") }
+	if opts.Addr == "" { opts.Addr = DefaultRecordAddr }
+	if opts.CADir == "" { opts.CADir = DefaultCADir() }
+
+	ca, err := loadLocalCA(opts.CADir)
+	if err != nil { return nil, err }
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil { return nil, fmt.Errorf("프록시 주소를 열 수 없습니다 (%s): %w", opts.Addr, err) }
+
+	// 기록한 페이지만 처리하므로 링크 추적은 하지 않음 (기록된 페이지 사이의 링크는 로컬 경로로 변환)
+	m.recording = true
+	m.opts.Depth = 0
+
+	return &Recorder{
+		m:         m,
+		ca:        ca,
+		listener:  listener,
+		transport: &http.Transport{Proxy: nil, ForceAttemptHTTP2: true, ResponseHeaderTimeout: max(m.opts.Timeouts.Request, 0)},
+		conns:     make(map[net.Conn]bool),
+	}, nil
+}
+
+// ProxyURL: 브라우저에 설정할 프록시 주소를 반환합니다.
+func (r *Recorder) ProxyURL() string { return "http://" + r.listener.Addr().String() }
+
+// CACertPath: 브라우저/OS에 신뢰 등록할 CA 인증서 경로를 반환합니다. (프록시 주소의 /ca.pem 으로도 받을 수 있음)
+func (r *Recorder) CACertPath() string { return r.ca.certPath }
+
+// Run: ctx가 취소될 때까지 요청을 기록한 뒤, 기록된 페이지로 미러를 작성합니다.
+// 미러 작성은 취소된 ctx와 관계없이 진행되며 Options.Timeouts.Total 제한을 받습니다.
+func (r *Recorder) Run(ctx context.Context) (*Result, error) {
+	server := &http.Server{Handler: r, ReadHeaderTimeout: 30 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(r.listener) }()
+
+	select {
+	case err := <-errCh:
+		r.m.Close()
+		return r.m.result(), err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	server.Shutdown(shutdownCtx)
+	cancel()
+	r.closeTunnels()
+	r.m.logf(" ⏹️  기록 종료: 페이지 %d개, 응답 %d개\n", len(r.m.recordedPages()), r.responses.Load())
+
+	return r.m.Run(context.WithoutCancel(ctx))
+}
+
+// ServeHTTP: 프록시 요청(절대 URL), HTTPS 터널(CONNECT), 프록시 자체로의 요청(CA 인증서 배포)을 구분합니다.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodConnect:
+		r.handleConnect(w, req)
+	case req.URL.IsAbs():
+		r.forward(w, req)
+	case req.URL.Path == "/ca.pem":
+		w.Header().Set("Content-Type", "application/x-pem-file")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+caCertFile+`"`)
+		http.ServeFile(w, req, r.ca.certPath)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "localizer 기록 프록시입니다. 브라우저의 프록시를 %s 로 설정하세요.\nHTTPS 사이트를 기록하려면 %s/ca.pem 을 신뢰할 수 있는 인증 기관으로 등록하세요.\n", r.ProxyURL(), r.ProxyURL())
+	}
+}
+
+// handleConnect: HTTPS 터널 요청을 가로채 로컬 CA가 발급한 인증서로 TLS를 종료하고, 내부 요청을 forward로 처리합니다.
+func (r *Recorder) handleConnect(w http.ResponseWriter, req *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil { return }
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		conn.Close()
+		return
+	}
+
+	connectHost := req.URL.Hostname()
+	tlsConn := tls.Server(conn, &tls.Config{
+		NextProtos: []string{"http/1.1"}, // 기록을 단순하게 하기 위해 HTTP/1.1만 사용
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			host := hello.ServerName
+			if host == "" { host = connectHost }
+			return r.ca.certFor(host)
+		},
+	})
+	tunnelConn := &notifyCloseConn{Conn: tlsConn, closed: make(chan struct{})}
+	r.trackTunnel(tunnelConn, true)
+	defer r.trackTunnel(tunnelConn, false)
+
+	// 터널 안의 요청은 일반 HTTP 서버로 읽고, https 절대 URL로 바꿔 전달
+	tunnel := &http.Server{
+		ReadHeaderTimeout: 30 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, in *http.Request) {
+			in.URL.Scheme = "https"
+			in.URL.Host = in.Host
+			if in.URL.Host == "" { in.URL.Host = req.Host }
+			r.forward(w, in)
+		}),
+	}
+	tunnel.Serve(&singleConnListener{conn: tunnelConn, closed: tunnelConn.closed})
+}
+
+// forward: 요청을 원래 서버로 전달하고, 받은 응답을 기록한 뒤 브라우저에 돌려줍니다.
+// 압축은 Transport가 해제하므로 브라우저와 저장소 모두 원본(비압축) 본문을 받습니다.
+func (r *Recorder) forward(w http.ResponseWriter, in *http.Request) {
+	if isUpgradeRequest(in) {
+		http.Error(w, "localizer 기록 프록시는 WebSocket 등 프로토콜 전환을 지원하지 않습니다", http.StatusNotImplemented)
+		return
+	}
+
+	out := in.Clone(in.Context())
+	out.RequestURI = ""
+	removeHopHeaders(out.Header)
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		r.m.logf(" ⚠️  요청 실패 (%s): %v\n", in.URL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	r.record(in, resp, body)
+
+	removeHopHeaders(resp.Header)
+	for key, vals := range resp.Header { w.Header()[key] = vals }
+	if in.Method != http.MethodHead && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+// record: 응답을 아카이브에 기록하고, GET 응답은 캡처 저장소(리소스) 또는 페이지 목록(HTML 문서)에 등록합니다.
+func (r *Recorder) record(in *http.Request, resp *http.Response, body []byte) {
+	r.responses.Add(1)
+	r.m.archive.recordResponse(resp, body)
+	if in.Method != http.MethodGet { return }
+
+	mimeType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	rec := &capturedResponse{
+		URL:      in.URL.String(),
+		Page:     in.Header.Get("Referer"),
+		Status:   resp.StatusCode,
+		MimeType: mimeType,
+		Type:     fetchDestType(in.Header.Get("Sec-Fetch-Dest"), in.Header.Get("Accept"), mimeType),
+		Body:     body,
+		Method:   in.Method,
+	}
+	rec.urls = []string{rec.URL}
+
+	if rec.Type == network.ResourceTypeDocument {
+		if resp.StatusCode != http.StatusOK || mimeType != "text/html" { return }
+		rec.Page = rec.URL
+		r.m.mu.Lock()
+		r.m.documents = append(r.m.documents, rec)
+		r.m.mu.Unlock()
+		r.m.logf(" 📝 페이지 기록: %s\n", rec.URL)
+		return
+	}
+	r.m.storeCaptured([]*capturedResponse{rec})
+}
+
+// fetchDestType: 요청의 Sec-Fetch-Dest 헤더(없으면 Accept와 응답 MIME 타입)로 리소스 종류를 판단합니다.
+// (Chrome은 http:// 사이트에는 Sec-Fetch-* 헤더를 보내지 않음)
+func fetchDestType(dest string, accept string, mimeType string) network.ResourceType {
+	switch dest {
+	case "document", "iframe", "frame":
+		return network.ResourceTypeDocument
+	case "style":
+		return network.ResourceTypeStylesheet
+	case "script", "worker", "sharedworker", "serviceworker":
+		return network.ResourceTypeScript
+	case "image":
+		return network.ResourceTypeImage
+	case "font":
+		return network.ResourceTypeFont
+	case "audio", "video":
+		return network.ResourceTypeMedia
+	case "track":
+		return network.ResourceTypeTextTrack
+	case "manifest":
+		return network.ResourceTypeManifest
+	case "":
+	default:
+		return network.ResourceTypeFetch
+	}
+
+	switch {
+	case mimeType == "text/html" && strings.Contains(accept, "text/html"):
+		return network.ResourceTypeDocument
+	case mimeType == "text/css":
+		return network.ResourceTypeStylesheet
+	case strings.Contains(mimeType, "javascript"):
+		return network.ResourceTypeScript
+	case strings.HasPrefix(mimeType, "image/"):
+		return network.ResourceTypeImage
+	case strings.HasPrefix(mimeType, "font/"), strings.Contains(mimeType, "font"):
+		return network.ResourceTypeFont
+	case strings.HasPrefix(mimeType, "video/"), strings.HasPrefix(mimeType, "audio/"):
+		return network.ResourceTypeMedia
+	case mimeType == "text/vtt":
+		return network.ResourceTypeTextTrack
+	}
+	return network.ResourceTypeFetch
+}
+
+// recordedPages: 기록된 HTML 문서 중 Scope 안의 페이지를 방문 순서대로 반환합니다.
+func (m *Mirror) recordedPages() []pageRef {
+	m.mu.Lock()
+	docs := append([]*capturedResponse(nil), m.documents...)
+	m.mu.Unlock()
+
+	var pages []pageRef
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		u, err := url.Parse(stripFragment(doc.URL))
+		if err != nil || !m.inScope(u) { continue }
+		page := pageRef{Source: u.String(), OutRel: m.pageOutPath(u)}
+		if seen[page.OutRel] { continue }
+		seen[page.OutRel] = true
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// recordedDocument: 페이지 URL의 기록된 HTML을 반환합니다. (여러 번 방문했으면 마지막 응답)
+func (m *Mirror) recordedDocument(pageURL string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.documents) - 1; i >= 0; i-- {
+		if stripFragment(m.documents[i].URL) == pageURL { return m.documents[i].Body, true }
+	}
+	return nil, false
+}
+
+// runRecorded: 기록된 페이지들을 처리합니다. 리소스는 기록된 응답을 우선 사용하고, 없으면 HTTP로 받습니다.
+func (m *Mirror) runRecorded(ctx context.Context) error {
+	pages := m.recordedPages()
+	if len(pages) == 0 { return fmt.Errorf("기록된 페이지가 없습니다 (시작 URL의 범위 안에서 방문한 HTML 문서가 필요합니다)") }
+	for _, page := range pages { m.schedulePage(page) }
+	m.processPages(ctx, pages)
+	if ctx.Err() != nil { return timeoutCause(ctx, ctx.Err()) }
+	return nil
+}
+
+func (r *Recorder) trackTunnel(conn net.Conn, open bool) {
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+	if open { r.conns[conn] = true } else { delete(r.conns, conn) }
+}
+
+// closeTunnels: 브라우저가 유지 중인 HTTPS 터널을 닫습니다. (http.Server.Shutdown은 가로챈 연결을 닫지 않음)
+func (r *Recorder) closeTunnels() {
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+	for conn := range r.conns { conn.Close() }
+}
+
+// hopHeaders: 프록시가 전달하지 않는 연결 단위 헤더 (RFC 9110 7.6.1)
+var hopHeaders = []string{"Connection", "Proxy-Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade"}
+
+func removeHopHeaders(h http.Header) {
+	for _, f := range strings.Split(h.Get("Connection"), ",") {
+		if f = strings.TrimSpace(f); f != "" { h.Del(f) }
+	}
+	for _, key := range hopHeaders { h.Del(key) }
+}
+
+func isUpgradeRequest(req *http.Request) bool {
+	return req.Header.Get("Upgrade") != "" && strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade")
+}
+
+// singleConnListener: 연결 하나만 반환하는 net.Listener (HTTPS 터널 안의 요청을 http.Server로 처리하기 위함)
+// 두 번째 Accept는 연결이 닫힐 때까지 기다렸다가 오류를 반환하므로, 연결이 끝나면 Serve도 끝납니다.
+type singleConnListener struct {
+	conn     net.Conn
+	accepted bool
+	closed   chan struct{}
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	if !l.accepted {
+		l.accepted = true
+		return l.conn, nil
+	}
+	<-l.closed
+	return nil, net.ErrClosed
+}
+
+func (l *singleConnListener) Close() error   { return nil }
+func (l *singleConnListener) Addr() net.Addr { return l.conn.LocalAddr() }
+
+// notifyCloseConn: 연결이 처음 닫힐 때 closed 채널을 닫습니다.
+type notifyCloseConn struct {
+	net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *notifyCloseConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { close(c.closed) })
+	return err
+}
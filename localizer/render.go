@@ -0,0 +1,69 @@
+package localizer
+
+import (
+	"context"
+
+	"github.com/chromedp/chromedp"
+)
+
+// fetchRenderedHTML: 공유 브라우저에 새 탭을 열어 웹페이지를 렌더링하고 HTML을 반환합니다.
+func (m *Mirror) fetchRenderedHTML(ctx context.Context, urlStr string) (_ []byte, err error) {
+	taskCtx, release, err := m.browser.newTab(ctx)
+	if err != nil { return nil, timeoutCause(ctx, err) }
+	defer release()
+
+	// 전체 작업이 취소되어 탭이 닫힌 경우, 탭 오류 대신 원래 원인을 반환
+	defer func() {
+		if err != nil && ctx.Err() != nil { err = timeoutCause(ctx, ctx.Err()) }
+	}()
+
+	// 페이지별 렌더링 제한 (Timeouts.Render, 0이면 무제한)
+	timeouts := m.opts.Timeouts
+	if timeouts.Render > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeoutCause(taskCtx, timeouts.Render, &TimeoutError{Budget: BudgetRender, Limit: timeouts.Render})
+		defer cancel()
+	}
+
+	// 탭을 먼저 생성한 뒤 이벤트 추적을 시작해야 이동 중 발생하는 요청을 놓치지 않음
+	if err := chromedp.Run(taskCtx); err != nil { return nil, timeoutCause(taskCtx, err) }
+	events := newPageEvents()
+	chromedp.ListenTarget(taskCtx, events.handle)
+	var recorder *responseRecorder
+	if !m.opts.DisableCapture || m.archive != nil {
+		recorder = newResponseRecorder(urlStr)
+		chromedp.ListenTarget(taskCtx, recorder.handle)
+	}
+
+	// 사이트 호스트로 가는 요청에 추가 헤더/인증 정보 적용 (쿠키와 User-Agent는 브라우저 실행 시 설정됨)
+	if err := m.injectSiteHeaders(taskCtx); err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	err = chromedp.Run(taskCtx,
+		chromedp.EmulateViewport(1920, 1080),
+		m.navigateAction(urlStr),
+	)
+	if err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	// 렌더링 완료 조건 대기 (기본: Timeouts.Settle 고정 대기)
+	if err := m.waitRendered(taskCtx, events, urlStr); err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	// 단계 파일의 조작(로그인, 배너 닫기, 탭 클릭 등)을 수행한 뒤 캡처
+	if err := m.runSteps(taskCtx, urlStr); err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	// 지연 로딩 이미지와 무한 스크롤 내용을 불러오도록 바닥까지 스크롤 (Options.Scroll)
+	if err := m.autoScroll(taskCtx, events, urlStr); err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	var res string
+	if err := chromedp.Run(taskCtx, chromedp.OuterHTML("html", &res)); err != nil { return nil, timeoutCause(taskCtx, err) }
+
+	// 렌더링 중 갱신된 쿠키를 이후 HTTP 요청에도 사용
+	m.syncBrowserCookies(taskCtx)
+
+	// 브라우저가 받은 응답 본문을 탭이 닫히기 전에 저장소로 옮기고, 아카이브에도 기록
+	if recorder != nil {
+		responses := recorder.collect(taskCtx, m.archive != nil)
+		if !m.opts.DisableCapture { m.storeCaptured(responses) }
+		m.archive.recordCaptured(responses)
+	}
+	return []byte(res), nil
+}
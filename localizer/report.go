@@ -0,0 +1,209 @@
+package localizer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// [실행 보고서 (JSON)]
+// ==========================================
+
+// Report: Options.Report 경로에 저장되는 실행 결과입니다. (CI에서 실패한 참조를 확인하는 용도)
+type Report struct {
+	Source     string           `json:"source"`
+	OutputDir  string           `json:"output_dir"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	DurationMs int64            `json:"duration_ms"`
+	Error      string           `json:"error,omitempty"` // 작업 전체가 실패/중단된 경우의 원인
+	Summary    ReportSummary    `json:"summary"`
+	Pages      []PageReport     `json:"pages"`
+	Resources  []ResourceReport `json:"resources"`
+}
+
+// ReportSummary: 보고서 요약 (Failed가 0이 아니면 깨진 참조가 있는 미러)
+type ReportSummary struct {
+	Files       int   `json:"files"`
+	Bytes       int64 `json:"bytes"`
+	Pages       int   `json:"pages"`
+	PagesFailed int   `json:"pages_failed"`
+	References  int   `json:"references"`
+	Saved       int   `json:"saved"`
+	Skipped     int   `json:"skipped"`
+	Failed      int   `json:"failed"`
+}
+
+// PageReport: 처리한 HTML 페이지 하나의 결과
+type PageReport struct {
+	Source     string `json:"source"`
+	SavedPath  string `json:"saved_path"`
+	Outcome    string `json:"outcome"` // OutcomeSaved 또는 OutcomeFailed
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ResourceReport: 발견된 리소스 참조 하나의 결과. 같은 URL을 여러 곳에서 참조하면 참조마다 항목이 생깁니다.
+type ResourceReport struct {
+	Page       string `json:"page"`                 // 참조가 있는 문서 (HTML 페이지 또는 CSS 파일)
+	Element    string `json:"element,omitempty"`    // 요소 이름 (CSS 파일 내부 참조는 "css")
+	Attribute  string `json:"attribute,omitempty"`  // 속성 이름 (CSS 내부 참조는 "url()", "@import")
+	Reference  string `json:"reference"`            // 문서에 적힌 원래 값
+	URL        string `json:"url,omitempty"`        // 해석된 절대 URL (로컬 모드는 파일 경로)
+	SavedPath  string `json:"saved_path,omitempty"` // 출력 폴더 기준 저장 경로
+	Fetch      string `json:"fetch,omitempty"`      // 내용을 얻은 방법 (http, captured, file, disk)
+	Status     int    `json:"status,omitempty"`     // HTTP 상태 코드 (캡처된 응답 포함)
+	Size       int64  `json:"size,omitempty"`       // 받은 바이트 수
+	DurationMs int64  `json:"duration_ms"`          // 내용을 얻는 데 걸린 시간
+	Outcome    string `json:"outcome"`              // OutcomeSaved, OutcomeSkipped, OutcomeFailed
+	Error      string `json:"error,omitempty"`
+}
+
+const (
+	OutcomeSaved   = "saved"   // 저장(또는 내장/아카이브 기록)되어 로컬 참조로 변환됨
+	OutcomeSkipped = "skipped" // 크기 제한 등 설정에 따라 받지 않음 (원본 URL 유지)
+	OutcomeFailed  = "failed"  // 다운로드/읽기 실패 (원본 URL 유지)
+)
+
+// refSource: 참조가 발견된 위치 (보고서용)
+type refSource struct {
+	Page      string
+	Element   string
+	Attribute string
+}
+
+// fetchInfo: URL 하나의 실제 다운로드 결과 (같은 URL을 가리키는 참조들이 공유)
+type fetchInfo struct {
+	method   string
+	status   int
+	size     int64
+	duration time.Duration
+}
+
+// runReport: 실행 중 발생한 페이지/리소스 결과를 모읍니다. (여러 고루틴에서 호출)
+type runReport struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	pages     []PageReport
+	resources []ResourceReport
+	fetches   map[string]fetchInfo
+}
+
+func newRunReport() *runReport {
+	return &runReport{startedAt: time.Now(), fetches: make(map[string]fetchInfo)}
+}
+
+// addFetch: URL의 다운로드 결과를 기록합니다. (실패한 요청도 상태 코드와 시간을 남김)
+func (r *runReport) addFetch(targetURL string, info fetchInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetches[targetURL] = info
+}
+
+// addReference: 리소스 참조 하나의 결과를 기록합니다.
+func (r *runReport) addReference(from refSource, reference string, targetURL string, saveRelPath string, err error) {
+	entry := ResourceReport{
+		Page:      from.Page,
+		Element:   from.Element,
+		Attribute: from.Attribute,
+		Reference: reference,
+		URL:       targetURL,
+		Outcome:   OutcomeSaved,
+	}
+	var tooLarge *sizeLimitError
+	switch {
+	case errors.As(err, &tooLarge):
+		entry.Outcome, entry.Error = OutcomeSkipped, err.Error()
+	case err != nil:
+		entry.Outcome, entry.Error = OutcomeFailed, err.Error()
+	default:
+		entry.SavedPath = filepath.ToSlash(saveRelPath)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resources = append(r.resources, entry)
+}
+
+// addPage: 페이지 처리 결과를 기록합니다.
+func (r *runReport) addPage(page pageRef, err error, duration time.Duration) {
+	entry := PageReport{Source: page.Source, SavedPath: filepath.ToSlash(page.OutRel), Outcome: OutcomeSaved, DurationMs: duration.Milliseconds()}
+	if err != nil { entry.Outcome, entry.Error = OutcomeFailed, err.Error() }
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages = append(r.pages, entry)
+}
+
+// failed: 실패한 리소스 참조 수
+func (r *runReport) failed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, res := range r.resources {
+		if res.Outcome == OutcomeFailed { n++ }
+	}
+	return n
+}
+
+// build: 모은 결과를 정렬하고 다운로드 결과를 참조마다 채워 Report를 만듭니다.
+func (r *runReport) build(m *Mirror, result *Result, runErr error) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	finished := time.Now()
+	rep := &Report{
+		Source:     m.opts.Source,
+		OutputDir:  m.opts.OutputDir,
+		StartedAt:  r.startedAt,
+		FinishedAt: finished,
+		DurationMs: finished.Sub(r.startedAt).Milliseconds(),
+		Pages:      append([]PageReport{}, r.pages...),
+		Resources:  make([]ResourceReport, 0, len(r.resources)),
+	}
+	if runErr != nil { rep.Error = runErr.Error() }
+
+	for _, entry := range r.resources {
+		if info, ok := r.fetches[entry.URL]; ok {
+			entry.Fetch, entry.Status, entry.Size, entry.DurationMs = info.method, info.status, info.size, info.duration.Milliseconds()
+		}
+		rep.Resources = append(rep.Resources, entry)
+	}
+	sort.SliceStable(rep.Pages, func(i, j int) bool { return rep.Pages[i].SavedPath < rep.Pages[j].SavedPath })
+	sort.SliceStable(rep.Resources, func(i, j int) bool {
+		a, b := rep.Resources[i], rep.Resources[j]
+		if a.Page != b.Page { return a.Page < b.Page }
+		return a.Reference < b.Reference
+	})
+
+	s := &rep.Summary
+	s.Files, s.Bytes = result.Files, result.Bytes
+	s.Pages, s.References = len(rep.Pages), len(rep.Resources)
+	for _, p := range rep.Pages {
+		if p.Outcome == OutcomeFailed { s.PagesFailed++ }
+	}
+	for _, res := range rep.Resources {
+		switch res.Outcome {
+		case OutcomeSaved:
+			s.Saved++
+		case OutcomeSkipped:
+			s.Skipped++
+		case OutcomeFailed:
+			s.Failed++
+		}
+	}
+	return rep
+}
+
+// writeReport: 보고서를 JSON 파일로 저장합니다.
+func writeReport(path string, rep *Report) error {
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil { return err }
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil { return err }
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
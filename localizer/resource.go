@@ -0,0 +1,319 @@
+package localizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ==========================================
+// [리소스 다운로드 및 CSS 처리 함수들]
+// ==========================================
+
+// resourceOpts: 참조 위치에 따라 달라지는 다운로드 옵션입니다.
+type resourceOpts struct {
+	maxBytes int64     // 이 크기를 넘으면 받지 않고 *sizeLimitError 반환 (0이면 무제한)
+	css      bool      // 확장자와 관계없이 CSS로 처리 (@import 대상, 저장 파일명에 .css 부착)
+	js       bool      // import 대상 (확장자가 없거나 .ts 등 소스 확장자이면 저장 파일명에 .js 부착, moduleFileName)
+	from     refSource // 참조가 발견된 위치 (실행 보고서용)
+}
+
+// downloadResource: 리소스를 다운로드하고 저장합니다. (중복 확인 및 캐싱 포함)
+func (m *Mirror) downloadResource(ctx context.Context, urlOrPath string, contextStr string, from refSource) (string, error) {
+	return m.downloadResourceWith(ctx, urlOrPath, contextStr, resourceOpts{from: from})
+}
+
+// downloadResourceWith: resourceOpts를 적용하여 리소스를 다운로드하고 저장합니다.
+// 이미 처리된 URL을 다시 참조한 경우를 포함해 참조마다 결과를 실행 보고서에 남깁니다.
+func (m *Mirror) downloadResourceWith(ctx context.Context, urlOrPath string, contextStr string, opts resourceOpts) (saveRelPath string, err error) {
+	targetURL, isRemote, err := m.resolveResource(urlOrPath, contextStr)
+	defer func() { m.report.addReference(opts.from, urlOrPath, targetURL, saveRelPath, err) }()
+	if err != nil { return "", err }
+	if ctx.Err() != nil { return "", ctx.Err() }
+
+	// [중복 방지] 완료된 URL은 기록을 재사용하고, 동일 URL을 다운로드 중이면 그 결과를 기다림
+	m.mu.Lock()
+	if savedRelPath, ok := m.processedFiles[targetURL]; ok {
+		m.mu.Unlock()
+		return savedRelPath, nil
+	}
+	if call, ok := m.inflight[targetURL]; ok {
+		m.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.path, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	m.inflight[targetURL] = call
+	m.mu.Unlock()
+
+	saveRelPath, data, cached, err := m.fetchResource(ctx, targetURL, isRemote, opts)
+	var tooLarge *sizeLimitError
+	if err != nil && ctx.Err() == nil && !errors.As(err, &tooLarge) { m.logf("           └── ⚠️  실패: %s (%v)\n", targetURL, err) }
+
+	// 경로가 확정되면 즉시 기록하여, CSS 순환 참조(a.css <-> b.css)도 대기 없이 처리되도록 함
+	m.mu.Lock()
+	delete(m.inflight, targetURL)
+	if err == nil {
+		m.processedFiles[targetURL] = saveRelPath
+		m.reserveStored(saveRelPath)
+	}
+	m.mu.Unlock()
+	call.path, call.err = saveRelPath, err
+	close(call.done)
+	if err != nil { return "", err }
+	// 저장(또는 단일 파일 모드의 보관)까지 끝나면 내용을 기다리는 쪽(SRI 계산, 내장)에 알림
+	defer func() { m.markStored(saveRelPath, data) }()
+
+	saveFullPath := filepath.Join(m.opts.OutputDir, saveRelPath)
+	displayPath := "/" + filepath.ToSlash(filepath.Join(filepath.Base(m.opts.OutputDir), saveRelPath))
+
+	var newContext string
+	if isRemote { newContext = targetURL } else { newContext = filepath.Dir(localPath(contextStr, urlOrPath)) }
+	docPage := targetURL // 보고서에 남길 CSS/스크립트 위치
+	if !isRemote { docPage = localPath(contextStr, urlOrPath) }
+
+	// [캐싱] 이미 존재하던 파일: CSS/스크립트라면 내부 파싱만 다시 수행
+	if cached {
+		m.logf("           └── %s (Cached)\n", displayPath)
+		data = m.processNested(ctx, data, saveRelPath, newContext, docPage)
+		return saveRelPath, nil
+	}
+
+	// CSS/스크립트 파일 내부 파싱 (재귀)
+	data = m.processNested(ctx, data, saveRelPath, newContext, docPage)
+
+	// 단일 파일 모드: 디스크 대신 메모리에 보관하여 HTML/CSS에 내장
+	if m.singleFile() {
+		m.logf("           └── %s (Inline)\n", displayPath)
+		return saveRelPath, nil
+	}
+
+	// 아카이브 전용 모드: 내용은 이미 아카이브에 기록되었으므로 파일로 저장하지 않음
+	if m.opts.Format == FormatArchive {
+		m.updateStats(int64(len(data)))
+		m.logf("           └── %s (Archived)\n", targetURL)
+		return saveRelPath, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(saveFullPath), 0755); err != nil { return "", err }
+	if err := os.WriteFile(saveFullPath, data, 0644); err != nil { return "", err }
+
+	m.updateStats(int64(len(data)))
+	m.logf("           └── %s\n", displayPath)
+	return saveRelPath, nil
+}
+
+// processNested: CSS(url(), @import)와 스크립트(import) 안의 참조를 재귀적으로 처리합니다. (그 밖의 리소스는 그대로 반환)
+// docPage: 보고서에 남길 CSS/스크립트 위치
+func (m *Mirror) processNested(ctx context.Context, data []byte, saveRelPath string, contextURL string, docPage string) []byte {
+	savedDir := filepath.Dir(saveRelPath)
+	switch {
+	case strings.HasSuffix(strings.ToLower(saveRelPath), ".css"):
+		return m.processCSSContent(ctx, data, contextURL, savedDir, refSource{Page: docPage, Element: "css"})
+	case isScriptPath(saveRelPath):
+		return m.processJSContent(ctx, data, contextURL, savedDir, refSource{Page: docPage, Element: "js"})
+	}
+	return data
+}
+
+// hasNestedRefs: 저장하면서 내부 참조를 바꾸는 리소스(CSS, 스크립트)인지 확인합니다.
+func hasNestedRefs(saveRelPath string) bool {
+	return strings.HasSuffix(strings.ToLower(saveRelPath), ".css") || isScriptPath(saveRelPath)
+}
+
+// resolveResource: 참조값을 다운로드 대상 절대 URL(원격) 또는 파일 경로(로컬)로 변환합니다.
+func (m *Mirror) resolveResource(urlOrPath string, contextStr string) (targetURL string, isRemote bool, err error) {
+	if strings.HasPrefix(contextStr, "http") {
+		baseURL, err := url.Parse(contextStr)
+		if err != nil { return "", false, err }
+		relURL, err := url.Parse(urlOrPath)
+		if err != nil { return "", false, err }
+		resolved := baseURL.ResolveReference(relURL)
+		resolved.Fragment = "" // #fragment는 같은 리소스이므로 제거
+		return resolved.String(), true, nil
+	}
+	if strings.HasPrefix(urlOrPath, "http") { return urlOrPath, true, nil }
+	if strings.HasPrefix(urlOrPath, "//") { return "https:" + urlOrPath, true, nil }
+	return filepath.Join(m.rootDir, localPath(contextStr, urlOrPath)), false, nil
+}
+
+// fetchResource: 리소스 내용을 가져오고 Naming 전략에 따라 저장 경로를 결정합니다.
+// 디스크에 이미 저장된 파일이 있으면 그 내용을 사용하고 cached=true를 반환합니다.
+// 실제 네트워크/파일 읽기는 sem으로 동시 실행 수가 제한됩니다.
+func (m *Mirror) fetchResource(ctx context.Context, targetURL string, isRemote bool, opts resourceOpts) (saveRelPath string, data []byte, cached bool, err error) {
+	u, _ := url.Parse(targetURL)
+	if u == nil { u = &url.URL{} }
+	fileName := resourceFileName(u, targetURL, isRemote)
+	if opts.css && !strings.EqualFold(filepath.Ext(fileName), ".css") { fileName += ".css" } // 예: fonts.googleapis.com/css?family=...
+	if opts.js { fileName = moduleFileName(fileName) }                                          // 예: import "/src/main.ts", "https://esm.sh/react"
+
+	targetSubDir := AssetDir
+	if isFontFile(fileName) { targetSubDir = FontDir }
+
+	// 워커 풀 슬롯 확보
+	select {
+	case m.sem <- struct{}{}:
+	case <-ctx.Done():
+		return "", nil, false, ctx.Err()
+	}
+	defer func() { <-m.sem }()
+
+	if m.opts.Naming == NamingHash {
+		// 내용 해시가 필요하므로 먼저 다운로드한 뒤 이름을 결정 (같은 내용은 한 파일로 합쳐짐)
+		data, err = m.readResource(ctx, targetURL, isRemote, opts.maxBytes)
+		if err != nil { return "", nil, false, err }
+		// CSS/스크립트는 내부 상대 참조가 URL 기준으로 바뀌므로, 내용이 같아도 URL이 다르면 다른 파일로 저장
+		hashInput := data
+		if hasNestedRefs(fileName) { hashInput = append([]byte(targetURL+"\n"), data...) }
+		saveRelPath, shared := m.claimPath(filepath.Join(targetSubDir, hashedName(fileName, hashInput)), "")
+		return saveRelPath, data, shared || m.savedOnDisk(saveRelPath), nil
+	}
+
+	saveRelPath, _ = m.claimPath(filepath.Join(targetSubDir, m.hierarchyPath(u, targetURL, isRemote, fileName)), targetURL)
+	saveFullPath := filepath.Join(m.opts.OutputDir, saveRelPath)
+
+	// [캐싱] 이미 존재하는 파일이면 다운로드 스킵
+	// (CSS/스크립트는 저장본의 참조가 이미 출력 폴더 기준으로 바뀌어 있으므로 원본을 다시 읽어 처리)
+	if m.savedOnDisk(saveRelPath) && !hasNestedRefs(saveRelPath) {
+		data, err = os.ReadFile(saveFullPath)
+		m.report.addFetch(targetURL, fetchInfo{method: "disk", size: int64(len(data))})
+		return saveRelPath, data, true, err
+	}
+
+	data, err = m.readResource(ctx, targetURL, isRemote, opts.maxBytes)
+	if err != nil { return "", nil, false, err }
+	return saveRelPath, data, false, nil
+}
+
+// readResource: 원격 URL은 HTTP로, 로컬 경로는 파일 시스템에서 내용을 읽습니다.
+// maxBytes가 0보다 크면 그보다 큰 리소스는 끝까지 받지 않고 *sizeLimitError를 반환합니다.
+func (m *Mirror) readResource(ctx context.Context, targetURL string, isRemote bool, maxBytes int64) (data []byte, err error) {
+	// 실패한 요청도 상태 코드와 걸린 시간을 보고서에 남김
+	start := time.Now()
+	info := fetchInfo{method: "http"}
+	defer func() {
+		info.size, info.duration = int64(len(data)), time.Since(start)
+		m.report.addFetch(targetURL, info)
+	}()
+
+	if !isRemote {
+		info.method = "file"
+		if maxBytes > 0 {
+			if info, err := os.Stat(stripQuery(targetURL)); err == nil && info.Size() > maxBytes { return nil, &sizeLimitError{Size: info.Size(), Limit: maxBytes} }
+		}
+		return os.ReadFile(stripQuery(targetURL))
+	}
+
+	// 브라우저 렌더링 중 이미 받은 응답이면 그대로 사용 (같은 쿠키/User-Agent로 받은 내용)
+	if rec, ok := m.capturedFor(targetURL); ok {
+		info.method, info.status = "captured", rec.Status
+		body := rec.Body
+		if maxBytes > 0 && int64(len(body)) > maxBytes { return nil, &sizeLimitError{Size: int64(len(body)), Limit: maxBytes} }
+		return body, nil
+	}
+
+	req, err := m.newRequest(ctx, "GET", targetURL)
+	if err != nil { return nil, err }
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil { return nil, m.requestTimeout(ctx, err) }
+	defer resp.Body.Close()
+	info.status = resp.StatusCode
+	// 아카이브에는 오류 응답도 기록하므로 본문을 읽은 뒤 상태를 확인
+	if resp.StatusCode != 200 && m.archive == nil { return nil, fmt.Errorf("status %d", resp.StatusCode) }
+	// 크기 제한: Content-Length로 먼저 확인하고, 길이를 모르면 제한까지만 읽어 판단
+	if maxBytes > 0 && resp.ContentLength > maxBytes { return nil, &sizeLimitError{Size: resp.ContentLength, Limit: maxBytes} }
+	body := io.Reader(resp.Body)
+	if maxBytes > 0 { body = io.LimitReader(resp.Body, maxBytes+1) }
+	data, err = io.ReadAll(body)
+	if err != nil { return nil, m.requestTimeout(ctx, err) }
+	if maxBytes > 0 && int64(len(data)) > maxBytes { return nil, &sizeLimitError{Limit: maxBytes} }
+	m.archive.recordResponse(resp, data)
+	if resp.StatusCode != 200 { return nil, fmt.Errorf("status %d", resp.StatusCode) }
+	return data, nil
+}
+
+// processCSSContent: CSS 내부의 URL 참조(url(), @import, image-set())를 찾아 리소스를 다운로드합니다.
+// @import 대상은 확장자와 관계없이 CSS로 처리하여 재귀적으로 분석하며, 참조 밖의 내용은 원문 그대로 유지됩니다.
+// 단일 파일 모드에서도 저장 경로 기준 상대 경로로 바꿔 두며, data: URI 변환은 HTML에 내장할 때 수행합니다.
+// from: 보고서에 남길 참조 위치 (Attribute가 비어 있으면 참조마다 "url()" 또는 "@import")
+func (m *Mirror) processCSSContent(ctx context.Context, cssData []byte, contextURL string, cssSavedDir string, from refSource) []byte {
+	if ctx.Err() != nil { return cssData }
+
+	cssStr := string(cssData)
+	refs := scanCSSRefs(cssStr)
+	if len(refs) == 0 { return cssData }
+
+	// 1단계: 참조된 리소스를 병렬로 다운로드하고 치환할 경로를 기록
+	absCssDir := filepath.Join(m.opts.OutputDir, cssSavedDir)
+	rewritten := make(map[string]string)
+	requested := make(map[string]bool)
+	var tasks taskGroup
+	for _, ref := range refs {
+		link := strings.TrimSpace(ref.url)
+		if shouldIgnoreLink(link) || requested[link] { continue }
+		requested[link] = true
+		refFrom := from
+		if refFrom.Attribute == "" {
+			refFrom.Attribute = "url()"
+			if ref.isImport { refFrom.Attribute = "@import" }
+		}
+
+		tasks.Go(func() func() {
+			resourcePath, err := m.downloadResourceWith(ctx, link, contextURL, resourceOpts{css: ref.isImport, from: refFrom})
+			if err != nil { return nil }
+			absResourcePath := filepath.Join(m.opts.OutputDir, resourcePath)
+			relPath, err := filepath.Rel(absCssDir, absResourcePath)
+			if err != nil { return nil }
+			return func() { rewritten[link] = filepath.ToSlash(relPath) }
+		})
+	}
+	tasks.Wait()
+
+	// 2단계: 참조 위치만 치환
+	return []byte(rewriteCSS(cssStr, refs, func(ref cssRef) (string, bool) {
+		relPath, ok := rewritten[strings.TrimSpace(ref.url)]
+		return relPath, ok
+	}))
+}
+
+// resourceRef: fromDir(출력 폴더 안의 절대 경로)에서 저장된 리소스를 가리킬 참조값을 계산합니다.
+// 폴더 모드에서는 상대 경로를, 단일 파일 모드에서는 data: URI를 반환합니다.
+func (m *Mirror) resourceRef(ctx context.Context, fromDir string, saveRelPath string) (string, error) {
+	if m.singleFile() {
+		data, ok := m.inlineContent(ctx, saveRelPath, make(map[string]bool))
+		if !ok { return "", fmt.Errorf("내장할 리소스 내용이 없음 (%s)", saveRelPath) }
+		return dataURI(saveRelPath, data), nil
+	}
+	relPath, err := filepath.Rel(fromDir, filepath.Join(m.opts.OutputDir, saveRelPath))
+	if err != nil { return "", err }
+	return filepath.ToSlash(relPath), nil
+}
+
+// savedOnDisk: 이전 실행에서 저장된 파일이 있는지 확인합니다. (폴더 모드에서만 리소스를 디스크에 저장)
+func (m *Mirror) savedOnDisk(saveRelPath string) bool {
+	return m.opts.Format == FormatFolder && fileExists(filepath.Join(m.opts.OutputDir, saveRelPath))
+}
+
+func fileExists(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
+
+func isFontFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch ext {
+	case ".woff", ".woff2", ".ttf", ".otf", ".eot": return true
+	}
+	return false
+}
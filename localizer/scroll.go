@@ -0,0 +1,104 @@
+package localizer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [자동 스크롤 (지연 로딩 / 무한 스크롤)]
+// ==========================================
+
+// Scroll: 캡처 전에 페이지를 아래로 조금씩 스크롤하여 loading="lazy" 이미지와 무한 스크롤 내용을 불러오는 방식입니다.
+// 스크롤할 때마다 새 네트워크 요청이 끝나기를 기다리고, 바닥에 닿은 뒤에도 페이지가 더 길어지지 않으면 멈춥니다.
+type Scroll struct {
+	Enabled   bool
+	Step      int           // 한 번에 스크롤할 픽셀 (0 이하이면 화면 높이)
+	MaxHeight int           // 이 위치(픽셀)까지만 스크롤 (0이면 DefaultScroll.MaxHeight, 음수이면 제한 없음)
+	MaxTime   time.Duration // 스크롤 전체 시간 제한 (0이면 DefaultScroll.MaxTime, 음수이면 렌더링 제한까지)
+	Settle    time.Duration // 스크롤마다 새 요청이 끝나기를 기다리는 최대 시간 (0이면 DefaultScroll.Settle)
+}
+
+// DefaultScroll: Scroll 필드가 0일 때 사용되는 기본값 (페이지 렌더링 제한 안에 끝나도록 설정)
+var DefaultScroll = Scroll{
+	MaxHeight: 50000,
+	MaxTime:   15 * time.Second,
+	Settle:    2 * time.Second,
+}
+
+// normalize: 0은 기본값으로, 음수 제한은 0(제한 없음)으로 변환합니다.
+func (s Scroll) normalize() Scroll {
+	if s.MaxHeight == 0 { s.MaxHeight = DefaultScroll.MaxHeight }
+	if s.MaxHeight < 0 { s.MaxHeight = 0 }
+	if s.MaxTime == 0 { s.MaxTime = DefaultScroll.MaxTime }
+	if s.MaxTime < 0 { s.MaxTime = 0 }
+	if s.Settle <= 0 { s.Settle = DefaultScroll.Settle }
+	return s
+}
+
+// scrollState: 스크롤 후 페이지 위치와 전체 높이 (픽셀)
+type scrollState struct {
+	Y      float64 `json:"y"`
+	View   float64 `json:"view"`
+	Height float64 `json:"height"`
+}
+
+// scrollStateJS: 현재 스크롤 위치와 페이지 높이를 반환하는 식
+const scrollStateJS = `(() => {
+	const el = document.scrollingElement || document.documentElement;
+	return {y: window.scrollY, view: window.innerHeight, height: el.scrollHeight};
+})()`
+
+// autoScroll: Options.Scroll이 켜져 있으면 페이지를 바닥(또는 제한)까지 스크롤하며 지연 로딩을 유도합니다.
+// 제한에 걸리면 경고만 출력하고, 마지막에 맨 위로 돌아가 새 요청이 끝나기를 기다린 뒤 반환합니다.
+func (m *Mirror) autoScroll(ctx context.Context, events *pageEvents, urlStr string) error {
+	s := m.opts.Scroll
+	if !s.Enabled { return nil }
+
+	scrollCtx, cancel := ctx, context.CancelFunc(func() {})
+	if s.MaxTime > 0 { scrollCtx, cancel = context.WithTimeout(ctx, s.MaxTime) }
+	defer cancel()
+
+	scrollJS := fmt.Sprintf(`window.scrollBy(0, %d || window.innerHeight)`, max(s.Step, 0))
+	var last scrollState
+	for steps := 0; ; steps++ {
+		// 한 단계 스크롤 -> 새 요청(지연 로딩 이미지, 다음 페이지 데이터)이 끝나기를 기다림 -> 늘어난 높이까지 측정
+		var state scrollState
+		err := chromedp.Run(scrollCtx, chromedp.Evaluate(scrollJS, nil))
+		if err == nil {
+			m.settleNetwork(scrollCtx, events)
+			err = chromedp.Run(scrollCtx, chromedp.Evaluate(scrollStateJS, &state))
+		}
+		if ctx.Err() != nil { return ctx.Err() }
+		if scrollCtx.Err() != nil {
+			m.logf(" ⚠️  자동 스크롤 시간 제한 도달 (%s, %s): 현재 상태로 캡처합니다\n", urlStr, s.MaxTime)
+			break
+		}
+		if err != nil {
+			m.logf(" ⚠️  자동 스크롤 중단 (%s): %v\n", urlStr, err)
+			break
+		}
+		if s.MaxHeight > 0 && state.Y+state.View >= float64(s.MaxHeight) {
+			m.logf(" ⚠️  자동 스크롤 높이 제한 도달 (%s, %dpx): 현재 상태로 캡처합니다\n", urlStr, s.MaxHeight)
+			break
+		}
+		// 더 내려가지 않고 페이지도 길어지지 않았으면 바닥 (무한 스크롤이 더 불러올 내용이 없음)
+		if steps > 0 && state.Y <= last.Y && state.Height <= last.Height { break }
+		last = state
+	}
+
+	// 고정 헤더 등이 스크롤된 상태로 저장되지 않도록 맨 위로 돌아감
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`window.scrollTo(0, 0)`, nil)); err != nil { return err }
+	m.settleNetwork(ctx, events)
+	return ctx.Err()
+}
+
+// settleNetwork: 진행 중인 요청이 Wait.Idle 동안 없을 때까지 최대 Scroll.Settle 만큼 기다립니다. (분석 스크립트 등 끝나지 않는 요청 대비)
+func (m *Mirror) settleNetwork(ctx context.Context, events *pageEvents) {
+	settleCtx, cancel := context.WithTimeout(ctx, m.opts.Scroll.Settle)
+	defer cancel()
+	events.waitIdle(settleCtx, m.opts.Wait.Idle)
+}
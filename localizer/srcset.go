@@ -0,0 +1,145 @@
+package localizer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ==========================================
+// [반응형 이미지 (srcset, <picture>, <source>)]
+// ==========================================
+
+// SrcsetMode: srcset 후보 이미지 중 다운로드할 범위입니다.
+type SrcsetMode string
+
+const (
+	SrcsetAll     SrcsetMode = "all"     // 모든 후보를 다운로드 (기본값)
+	SrcsetLargest SrcsetMode = "largest" // 가장 큰 후보 하나만 다운로드하고 srcset을 그 후보로 줄임
+)
+
+// ParseSrcsetMode: 문자열을 SrcsetMode로 변환합니다. 빈 문자열은 SrcsetAll로 취급합니다.
+func ParseSrcsetMode(s string) (SrcsetMode, bool) {
+	switch SrcsetMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", SrcsetAll:
+		return SrcsetAll, true
+	case SrcsetLargest:
+		return SrcsetLargest, true
+	}
+	return "", false
+}
+
+// srcCandidate: srcset 후보 하나 (URL + 설명자)
+type srcCandidate struct {
+	URL        string
+	Descriptor string // "480w", "2x" 등 (없으면 빈 문자열 = 1x)
+}
+
+// parseSrcset: HTML 표준의 srcset 파싱 규칙에 따라 후보 목록을 분리합니다.
+// URL은 공백 전까지이므로 URL 안의 쉼표(CDN 변환 파라미터, data: URI)도 유지되며,
+// 설명자는 괄호 밖의 쉼표까지 읽습니다.
+func parseSrcset(s string) []srcCandidate {
+	var cands []srcCandidate
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+	i := 0
+	for i < len(s) {
+		// 후보 사이의 공백과 쉼표 건너뛰기
+		for i < len(s) && (isSpace(s[i]) || s[i] == ',') { i++ }
+		if i >= len(s) { break }
+
+		start := i
+		for i < len(s) && !isSpace(s[i]) { i++ }
+		u := s[start:i]
+		// URL이 쉼표로 끝나면 설명자 없는 후보
+		if strings.HasSuffix(u, ",") {
+			cands = append(cands, srcCandidate{URL: strings.TrimRight(u, ",")})
+			continue
+		}
+
+		for i < len(s) && isSpace(s[i]) { i++ }
+		start = i
+		depth := 0
+		for i < len(s) {
+			if s[i] == '(' { depth++ }
+			if s[i] == ')' && depth > 0 { depth-- }
+			if s[i] == ',' && depth == 0 { break }
+			i++
+		}
+		cands = append(cands, srcCandidate{URL: u, Descriptor: strings.Join(strings.Fields(s[start:i]), " ")})
+		if i < len(s) { i++ } // 쉼표
+	}
+	return cands
+}
+
+// formatSrcset: 후보 목록을 srcset 속성값으로 직렬화합니다.
+func formatSrcset(cands []srcCandidate) string {
+	parts := make([]string, 0, len(cands))
+	for _, c := range cands {
+		if c.Descriptor == "" {
+			parts = append(parts, c.URL)
+		} else {
+			parts = append(parts, c.URL+" "+c.Descriptor)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// size: 설명자에서 너비(w)와 밀도(x)를 읽습니다. 설명자가 없으면 1x로 취급합니다.
+func (c srcCandidate) size() (width float64, density float64) {
+	density = 1
+	for _, d := range strings.Fields(c.Descriptor) {
+		if len(d) < 2 { continue }
+		v, err := strconv.ParseFloat(d[:len(d)-1], 64)
+		if err != nil { continue }
+		switch d[len(d)-1] {
+		case 'w':
+			width = v
+		case 'x':
+			density = v
+		}
+	}
+	return width, density
+}
+
+// largestCandidate: 너비 설명자(w)가 있으면 가장 넓은 후보를, 없으면 밀도(x)가 가장 높은 후보를 고릅니다.
+func largestCandidate(cands []srcCandidate) srcCandidate {
+	best := cands[0]
+	bestW, bestX := best.size()
+	for _, c := range cands[1:] {
+		w, x := c.size()
+		if w > bestW || (w == bestW && x > bestX) {
+			best, bestW, bestX = c, w, x
+		}
+	}
+	return best
+}
+
+// handleSrcset: srcset 계열 속성(srcset, data-srcset, imagesrcset)의 후보 이미지를 다운로드하고
+// 설명자를 유지한 채 URL만 로컬 경로로 바꿉니다. 다운로드에 실패한 후보는 원래 URL을 유지합니다.
+func (m *Mirror) handleSrcset(ctx context.Context, tasks *taskGroup, n *html.Node, attrName string, currentContext string, page pageRef) {
+	for _, a := range n.Attr {
+		if a.Key != attrName { continue }
+		cands := parseSrcset(a.Val)
+		if len(cands) == 0 { continue }
+		if m.opts.Srcset == SrcsetLargest { cands = []srcCandidate{largestCandidate(cands)} }
+
+		tasks.Go(func() func() {
+			// 후보들을 병렬로 다운로드한 뒤 속성값을 한 번에 다시 구성
+			var sub taskGroup
+			for j, c := range cands {
+				if shouldIgnoreLink(c.URL) { continue }
+				sub.Go(func() func() {
+					resourceRelPath, err := m.downloadResource(ctx, c.URL, currentContext, refSource{page.Source, n.Data, attrName})
+					if err != nil { return nil }
+					ref, err := m.resourceRef(ctx, m.pageOutputDir(page), resourceRelPath)
+					if err != nil { return nil }
+					return func() { cands[j].URL = ref }
+				})
+			}
+			sub.Wait()
+			return func() { setAttr(n, attrName, formatSrcset(cands)) }
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package localizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []srcCandidate
+	}{
+		{"a.jpg 1x, b.jpg 2x", []srcCandidate{{"a.jpg", "1x"}, {"b.jpg", "2x"}}},
+		{"a.jpg 480w,b.jpg 800w", []srcCandidate{{"a.jpg", "480w"}, {"b.jpg", "800w"}}},
+		// 설명자 없는 후보
+		{"a.jpg", []srcCandidate{{"a.jpg", ""}}},
+		{"a.jpg, b.jpg 2x", []srcCandidate{{"a.jpg", ""}, {"b.jpg", "2x"}}},
+		{"a.jpg,, b.jpg", []srcCandidate{{"a.jpg", ""}, {"b.jpg", ""}}},
+		{"a.jpg 2x, b.jpg", []srcCandidate{{"a.jpg", "2x"}, {"b.jpg", ""}}},
+		// URL 안의 쉼표 (CDN 변환 파라미터)
+		{"https://cdn.example.com/w_200,h_100/a.jpg 1x, https://cdn.example.com/w_400,h_200/a.jpg 2x",
+			[]srcCandidate{{"https://cdn.example.com/w_200,h_100/a.jpg", "1x"}, {"https://cdn.example.com/w_400,h_200/a.jpg", "2x"}}},
+		{"a.jpg?x=1,2 100w", []srcCandidate{{"a.jpg?x=1,2", "100w"}}},
+		// data: 후보 (쉼표가 포함된 URI)
+		{"data:image/gif;base64,R0lGODlhAQABAAAAACw= 1x, b.jpg 2x", []srcCandidate{{"data:image/gif;base64,R0lGODlhAQABAAAAACw=", "1x"}, {"b.jpg", "2x"}}},
+		{"data:image/svg+xml,%3Csvg%3E%3C/svg%3E", []srcCandidate{{"data:image/svg+xml,%3Csvg%3E%3C/svg%3E", ""}}},
+		// 공백/줄바꿈, 여러 설명자, 괄호 안의 쉼표
+		{"\n  a.jpg   100w  \n ,\tb.jpg 200w\n", []srcCandidate{{"a.jpg", "100w"}, {"b.jpg", "200w"}}},
+		{"a.jpg 100w 50h, b.jpg 200w", []srcCandidate{{"a.jpg", "100w 50h"}, {"b.jpg", "200w"}}},
+		{"a.jpg foo(1, 2), b.jpg", []srcCandidate{{"a.jpg", "foo(1, 2)"}, {"b.jpg", ""}}},
+		{"", nil},
+		{" , ", nil},
+	}
+	for _, tt := range tests {
+		if got := parseSrcset(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSrcset(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSrcsetRoundTrip(t *testing.T) {
+	in := "https://cdn.example.com/w_200,h_100/a.jpg 1x, b.jpg, data:image/gif;base64,R0lG 3x"
+	if got := formatSrcset(parseSrcset(in)); got != in {
+		t.Errorf("formatSrcset(parseSrcset(%q)) = %q", in, got)
+	}
+}
+
+func TestLargestCandidate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a.jpg 480w, b.jpg 1200w, c.jpg 800w", "b.jpg"},
+		{"a.jpg, b.jpg 3x, c.jpg 2x", "b.jpg"},
+		{"a.jpg, b.jpg 0.5x", "a.jpg"}, // 설명자 없음 = 1x
+	}
+	for _, tt := range tests {
+		if got := largestCandidate(parseSrcset(tt.in)).URL; got != tt.want {
+			t.Errorf("largestCandidate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package localizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
+)
+
+// ==========================================
+// [렌더링 전 조작 단계 (로그인, 배너 닫기 등)]
+// ==========================================
+
+// StepAction: 단계에서 수행할 브라우저 조작입니다.
+type StepAction string
+
+const (
+	StepNavigate StepAction = "navigate" // URL로 이동 (상대 경로는 렌더링 중인 페이지 기준, 비우면 그 페이지로 다시 이동)
+	StepClick    StepAction = "click"    // 선택자 요소가 보이면 클릭
+	StepType     StepAction = "type"     // 선택자 입력 요소의 값을 지우고 Text 입력
+	StepWait     StepAction = "wait"     // 선택자 요소가 보이거나, JS 식이 참이 되거나, Duration 만큼 대기
+	StepEval     StepAction = "eval"     // JavaScript 실행 (Promise이면 완료될 때까지 대기)
+	StepScroll   StepAction = "scroll"   // 선택자 요소가 보이도록, 또는 To(top/bottom)나 By(픽셀)만큼 스크롤
+)
+
+// ParseStepAction: 문자열을 StepAction으로 변환합니다.
+func ParseStepAction(s string) (StepAction, bool) {
+	switch StepAction(strings.ToLower(strings.TrimSpace(s))) {
+	case StepNavigate:
+		return StepNavigate, true
+	case StepClick:
+		return StepClick, true
+	case StepType:
+		return StepType, true
+	case StepWait:
+		return StepWait, true
+	case StepEval:
+		return StepEval, true
+	case StepScroll:
+		return StepScroll, true
+	}
+	return "", false
+}
+
+// DefaultStepTimeout: Step.Timeout 미지정 시 단계 하나의 시간 제한 (고정 대기 단계 제외)
+const DefaultStepTimeout = 10 * time.Second
+
+// Step: 원격 페이지를 렌더링한 뒤 HTML을 가져오기 전에 순서대로 수행하는 조작 하나입니다.
+type Step struct {
+	Action   StepAction
+	Selector string        // click, type, wait, scroll 대상 CSS 선택자
+	Text     string        // type: 입력할 텍스트
+	URL      string        // navigate: 이동할 주소
+	JS       string        // eval: 실행할 식, wait: 참이 될 때까지 기다릴 식
+	Duration time.Duration // wait: 고정 대기 시간
+	To       string        // scroll: "top" 또는 "bottom"
+	By       int           // scroll: 세로로 이동할 픽셀 (음수이면 위로)
+	Timeout  time.Duration // 단계 시간 제한 (0이면 DefaultStepTimeout)
+	Optional bool          // 실패해도 경고만 출력하고 다음 단계 진행 (나타나지 않을 수 있는 쿠키 배너 등)
+	Once     bool          // 시작 페이지를 렌더링할 때만 수행 (로그인 등, 쿠키는 이후 탭과 공유됨)
+}
+
+// validate: 조작에 필요한 값이 빠지지 않았는지 확인합니다.
+func (s Step) validate() error {
+	switch s.Action {
+	case StepNavigate:
+		return nil
+	case StepClick, StepType:
+		if s.Selector == "" { return fmt.Errorf("%s 단계에는 CSS 선택자가 필요합니다", s.Action) }
+	case StepWait:
+		if s.Selector == "" && s.JS == "" && s.Duration <= 0 { return fmt.Errorf("wait 단계에는 선택자, js 식, 대기 시간 중 하나가 필요합니다") }
+	case StepEval:
+		if s.JS == "" { return fmt.Errorf("eval 단계에는 JavaScript 식이 필요합니다") }
+	case StepScroll:
+		if s.To != "" && s.To != "top" && s.To != "bottom" { return fmt.Errorf("scroll 위치는 top 또는 bottom 이어야 합니다: %s", s.To) }
+		if s.Selector == "" && s.To == "" && s.By == 0 { return fmt.Errorf("scroll 단계에는 선택자, top/bottom, 픽셀 중 하나가 필요합니다") }
+	default:
+		return fmt.Errorf("알 수 없는 단계입니다: %s", s.Action)
+	}
+	return nil
+}
+
+// ==========================================
+// [단계 파일 읽기 (JSON / YAML)]
+// ==========================================
+
+// LoadSteps: 단계 파일을 읽습니다. 확장자가 .json이면 JSON 배열, 그 외(.yaml, .yml)는 YAML 목록으로 읽습니다.
+// 각 항목은 조작 이름 하나를 키로 갖고, 나머지 키는 보조 값입니다.
+//
+//	- click: "#accept-cookies"
+//	  optional: true
+//	- type: "#username"
+//	  text: admin
+//	- wait: 2s
+func LoadSteps(path string) ([]Step, error) {
+	data, err := os.ReadFile(path)
+	if err != nil { return nil, err }
+
+	var records []map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		records, err = parseJSONSteps(data)
+	} else {
+		records, err = parseYAMLSteps(data)
+	}
+	if err != nil { return nil, fmt.Errorf("%s: %w", path, err) }
+
+	steps := make([]Step, 0, len(records))
+	for i, fields := range records {
+		step, err := stepFromFields(fields)
+		if err != nil { return nil, fmt.Errorf("%s: 단계 %d: %w", path, i+1, err) }
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// stepFromFields: 키-값 목록을 Step으로 변환합니다. 조작 이름 키의 값은 조작의 주 대상(선택자, URL, 식)입니다.
+func stepFromFields(fields map[string]string) (Step, error) {
+	var step Step
+	for key := range fields {
+		action, ok := ParseStepAction(key)
+		if !ok { continue }
+		if step.Action != "" { return step, fmt.Errorf("한 단계에 조작이 여러 개입니다 (%s, %s)", step.Action, action) }
+		step.Action = action
+	}
+	if step.Action == "" { return step, fmt.Errorf("조작(navigate, click, type, wait, eval, scroll)이 없습니다") }
+
+	value := fields[string(step.Action)]
+	switch step.Action {
+	case StepNavigate:
+		step.URL = value
+	case StepClick, StepType:
+		step.Selector = value
+	case StepEval:
+		step.JS = value
+	case StepWait:
+		// "2s" 같은 값은 고정 대기 (CSS 선택자는 숫자로 시작할 수 없음)
+		if d, err := time.ParseDuration(value); err == nil {
+			step.Duration = d
+		} else {
+			step.Selector = value
+		}
+	case StepScroll:
+		if n, err := strconv.Atoi(value); err == nil {
+			step.By = n
+		} else if value == "top" || value == "bottom" {
+			step.To = value
+		} else {
+			step.Selector = value
+		}
+	}
+
+	for key, value := range fields {
+		if key == string(step.Action) { continue }
+		var err error
+		switch key {
+		case "text":
+			step.Text = value
+		case "js":
+			step.JS = value
+		case "timeout":
+			step.Timeout, err = time.ParseDuration(value)
+		case "optional":
+			step.Optional, err = parseStepBool(value)
+		case "once":
+			step.Once, err = parseStepBool(value)
+		default:
+			return step, fmt.Errorf("알 수 없는 키입니다: %s", key)
+		}
+		if err != nil { return step, fmt.Errorf("%s 값이 올바르지 않습니다: %s", key, value) }
+	}
+	return step, step.validate()
+}
+
+// parseStepBool: true/false 외에 YAML에서 흔히 쓰는 yes/no, on/off도 허용합니다.
+func parseStepBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// parseJSONSteps: 객체 배열을 읽어 각 객체의 스칼라 값을 문자열로 변환합니다.
+func parseJSONSteps(data []byte) ([]map[string]string, error) {
+	var items []map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&items); err != nil { return nil, fmt.Errorf("단계 목록(JSON 배열)을 읽을 수 없습니다: %w", err) }
+
+	records := make([]map[string]string, 0, len(items))
+	for i, item := range items {
+		fields := make(map[string]string, len(item))
+		for key, v := range item {
+			switch v := v.(type) {
+			case nil:
+				fields[key] = ""
+			case string:
+				fields[key] = v
+			case json.Number, bool:
+				fields[key] = fmt.Sprint(v)
+			default:
+				return nil, fmt.Errorf("단계 %d: %s 값은 문자열, 숫자, 불리언이어야 합니다", i+1, key)
+			}
+		}
+		records = append(records, fields)
+	}
+	return records, nil
+}
+
+// parseYAMLSteps: 매핑 목록을 읽어 각 매핑의 스칼라 값을 문자열로 변환합니다.
+// 값은 YAML이 해석한 그대로의 문자열이며(따옴표, 여러 줄 문자열, 별칭 처리 포함), null은 빈 값입니다.
+func parseYAMLSteps(data []byte) ([]map[string]string, error) {
+	var items []map[string]yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&items); err != nil && err != io.EOF { return nil, fmt.Errorf("단계 목록(YAML 목록)을 읽을 수 없습니다: %w", err) }
+	// 두 번째 문서(---)부터의 단계가 조용히 빠지지 않도록 오류로 처리
+	var rest yaml.Node
+	if err := dec.Decode(&rest); err != io.EOF { return nil, fmt.Errorf("단계 파일에는 YAML 문서가 하나만 있어야 합니다") }
+
+	records := make([]map[string]string, 0, len(items))
+	for i, item := range items {
+		fields := make(map[string]string, len(item))
+		for key, node := range item {
+			if node.Kind == yaml.AliasNode { node = *node.Alias }
+			switch {
+			case node.Kind != yaml.ScalarNode:
+				return nil, fmt.Errorf("단계 %d: %s 값은 문자열, 숫자, 불리언이어야 합니다 (%d번째 줄)", i+1, key, node.Line)
+			case node.ShortTag() == "!!null":
+				fields[key] = ""
+			default:
+				fields[key] = node.Value
+			}
+		}
+		records = append(records, fields)
+	}
+	return records, nil
+}
+
+// ==========================================
+// [단계 실행]
+// ==========================================
+
+// runSteps: 렌더링된 탭에서 Options.Steps를 순서대로 수행합니다.
+// Optional 단계의 실패는 경고만 출력하며, 그 외 단계가 실패하면 페이지 처리를 실패로 끝냅니다.
+func (m *Mirror) runSteps(ctx context.Context, urlStr string) error {
+	startPage := urlStr == m.startPage().Source
+	for i, step := range m.opts.Steps {
+		if step.Once && !startPage { continue }
+
+		stepCtx, cancel := ctx, context.CancelFunc(func() {})
+		timeout := step.Timeout
+		if timeout <= 0 { timeout = DefaultStepTimeout }
+		if step.Action != StepWait || step.Duration <= 0 { stepCtx, cancel = context.WithTimeout(ctx, timeout) }
+		err := chromedp.Run(stepCtx, m.stepAction(step, urlStr))
+		cancel()
+		if err == nil { continue }
+
+		if ctx.Err() != nil { return ctx.Err() }
+		if errors.Is(err, context.DeadlineExceeded) { err = fmt.Errorf("%s 안에 완료되지 않았습니다", timeout) }
+		if step.Optional {
+			m.logf(" ⚠️  단계 %d (%s) 건너뜀 (%s): %v\n", i+1, step.Action, urlStr, err)
+			continue
+		}
+		return fmt.Errorf("단계 %d (%s) 실패: %v", i+1, step.Action, err)
+	}
+	return nil
+}
+
+// stepAction: 단계를 chromedp 작업으로 변환합니다.
+func (m *Mirror) stepAction(step Step, urlStr string) chromedp.Action {
+	switch step.Action {
+	case StepNavigate:
+		target := urlStr
+		if step.URL != "" {
+			base, err := url.Parse(urlStr)
+			ref, err2 := url.Parse(step.URL)
+			if err != nil || err2 != nil { return chromedp.ActionFunc(func(context.Context) error { return fmt.Errorf("잘못된 URL입니다: %s", step.URL) }) }
+			target = base.ResolveReference(ref).String()
+		}
+		return chromedp.Navigate(target)
+	case StepClick:
+		return chromedp.Click(step.Selector, chromedp.ByQuery)
+	case StepType:
+		return chromedp.Tasks{
+			chromedp.WaitVisible(step.Selector, chromedp.ByQuery),
+			chromedp.SetValue(step.Selector, "", chromedp.ByQuery),
+			chromedp.SendKeys(step.Selector, step.Text, chromedp.ByQuery),
+		}
+	case StepWait:
+		switch {
+		case step.Selector != "":
+			return chromedp.WaitVisible(step.Selector, chromedp.ByQuery)
+		case step.JS != "":
+			return chromedp.Poll(step.JS, nil, chromedp.WithPollingInterval(100*time.Millisecond), chromedp.WithPollingTimeout(0))
+		}
+		return chromedp.Sleep(step.Duration)
+	case StepEval:
+		return chromedp.Evaluate(step.JS, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams { return p.WithAwaitPromise(true) })
+	case StepScroll:
+		switch {
+		case step.Selector != "":
+			return chromedp.ScrollIntoView(step.Selector, chromedp.ByQuery)
+		case step.To == "top":
+			return chromedp.Evaluate(`window.scrollTo(0, 0)`, nil)
+		case step.To == "bottom":
+			return chromedp.Evaluate(`window.scrollTo(0, document.documentElement.scrollHeight)`, nil)
+		}
+		return chromedp.Evaluate(fmt.Sprintf(`window.scrollBy(0, %d)`, step.By), nil)
+	}
+	return chromedp.ActionFunc(func(context.Context) error { return step.validate() })
+}
@@ -0,0 +1,106 @@
+package localizer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseYAMLSteps(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []map[string]string
+	}{
+		{"plain values", "- click: .cookie-accept\n  optional: true\n- wait: 2s\n", []map[string]string{{"click": ".cookie-accept", "optional": "true"}, {"wait": "2s"}}},
+		{"document start and comments", "# steps\n---\n- type: \"#search\"  # 입력창\n  text: localizer # 검색어\n\n# 끝\n", []map[string]string{{"type": "#search", "text": "localizer"}}},
+		{"dash on its own line", "-\n  navigate: /login\n  once: yes\n", []map[string]string{{"navigate": "/login", "once": "yes"}}},
+		{"indented list", "  - click: a\n    optional: on\n  - click: b\n", []map[string]string{{"click": "a", "optional": "on"}, {"click": "b"}}},
+		{"crlf", "- click: a\r\n  text: b\r\n", []map[string]string{{"click": "a", "text": "b"}}},
+		{"empty and null", "- navigate:\n  text: ~\n  js: null\n", []map[string]string{{"navigate": "", "text": "", "js": ""}}},
+		{"colon without space", "- click: a:hover\n  navigate: https://example.com/a#b\n", []map[string]string{{"click": "a:hover", "navigate": "https://example.com/a#b"}}},
+		{"single quoted", `- click: 'it''s #here' # c` + "\n", []map[string]string{{"click": "it's #here"}}},
+		{"double quoted escapes", `- text: "a\"b\\c\n\t\x41é #"` + "\n", []map[string]string{{"text": "a\"b\\c\n\tAé #"}}},
+		{"anchor and alias", "- click: &btn .x\n- click: *btn\n  optional: !!str true\n", []map[string]string{{"click": ".x"}, {"click": ".x", "optional": "true"}}},
+		{"multi-line plain", "- text: a\n    b\n", []map[string]string{{"text": "a b"}}},
+		{"literal block", "- eval: |\n    const a = 1;\n\n    a + 1\n  optional: true\n", []map[string]string{{"eval": "const a = 1;\n\na + 1\n", "optional": "true"}}},
+		{"literal strip", "- eval: |- # 주석\n    a\n    b\n", []map[string]string{{"eval": "a\nb"}}},
+		{"literal keep", "- eval: |+\n    a\n\n\n- click: b\n", []map[string]string{{"eval": "a\n\n\n"}, {"click": "b"}}},
+		{"folded block", "- eval: >\n    a\n    b\n\n    c\n      d\n    e\n", []map[string]string{{"eval": "a b\nc\n  d\ne\n"}}},
+		{"folded strip", "- text: >-\n   one\n   two\n", []map[string]string{{"text": "one two"}}},
+	}
+	for _, tt := range tests {
+		got, err := parseYAMLSteps([]byte(tt.yaml))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) { t.Errorf("%s: got %q, want %q", tt.name, got, tt.want) }
+	}
+}
+
+func TestParseYAMLStepsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"top-level mapping", "click: a\n"},
+		{"flow mapping value", "- click: {a: 1}\n"},
+		{"flow sequence value", "- click: [a, b]\n"},
+		{"nested list", "- - click: a\n"},
+		{"nested mapping", "- click:\n    selector: a\n"},
+		{"unclosed single quote", "- text: 'a\n"},
+		{"bad escape", `- text: "\q"` + "\n"},
+		{"tab indent", "- click: a\n\ttext: b\n"},
+		{"duplicate key", "- click: a\n  click: b\n"},
+		{"second document", "- click: a\n---\n- click: b\n"},
+	}
+	for _, tt := range tests {
+		if got, err := parseYAMLSteps([]byte(tt.yaml)); err == nil { t.Errorf("%s: parsed %q, want error", tt.name, got) }
+	}
+}
+
+func TestLoadSteps(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "steps.yaml")
+	yamlSrc := "- navigate: /login\n  once: true\n- type: '#user'\n  text: admin\n- click: button[type=submit]\n  timeout: 5s\n- wait: 2s\n- wait: .done\n- scroll: bottom\n- scroll: -300\n- eval: |\n    document.title\n  optional: yes\n"
+	jsonPath := filepath.Join(dir, "steps.json")
+	jsonSrc := `[{"navigate": "/login", "once": true}, {"type": "#user", "text": "admin"}, {"click": "button[type=submit]", "timeout": "5s"},
+		{"wait": "2s"}, {"wait": ".done"}, {"scroll": "bottom"}, {"scroll": -300}, {"eval": "document.title\n", "optional": true}]`
+	if err := os.WriteFile(yamlPath, []byte(yamlSrc), 0644); err != nil { t.Fatal(err) }
+	if err := os.WriteFile(jsonPath, []byte(jsonSrc), 0644); err != nil { t.Fatal(err) }
+
+	want := []Step{
+		{Action: StepNavigate, URL: "/login", Once: true},
+		{Action: StepType, Selector: "#user", Text: "admin"},
+		{Action: StepClick, Selector: "button[type=submit]", Timeout: 5 * time.Second},
+		{Action: StepWait, Duration: 2 * time.Second},
+		{Action: StepWait, Selector: ".done"},
+		{Action: StepScroll, To: "bottom"},
+		{Action: StepScroll, By: -300},
+		{Action: StepEval, JS: "document.title\n", Optional: true},
+	}
+	for _, path := range []string{yamlPath, jsonPath} {
+		got, err := LoadSteps(path)
+		if err != nil { t.Fatalf("LoadSteps(%s): %v", filepath.Base(path), err) }
+		if !reflect.DeepEqual(got, want) { t.Errorf("LoadSteps(%s):\n got %+v\nwant %+v", filepath.Base(path), got, want) }
+	}
+
+	for _, bad := range []string{
+		"- click: a\n  wait: 1s\n",   // 조작이 두 개
+		"- text: a\n",                // 조작 없음
+		"- click: a\n  color: red\n", // 알 수 없는 키
+		"- type: '#x'\n  optional: maybe\n",
+		"- scroll: middle\n  timeout: soon\n",
+		"- eval:\n",
+	} {
+		path := filepath.Join(dir, "bad.yml")
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil { t.Fatal(err) }
+		if _, err := LoadSteps(path); err == nil || !strings.Contains(err.Error(), "bad.yml") {
+			t.Errorf("LoadSteps(%q) error = %v, want error naming the file", bad, err)
+		}
+	}
+}
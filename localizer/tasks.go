@@ -0,0 +1,55 @@
+package localizer
+
+import "sync"
+
+// ==========================================
+// [병렬 처리 보조 타입]
+// ==========================================
+
+// inflightCall: 다운로드 진행 중인 URL의 결과를 기다리는 다른 요청들과 공유합니다.
+type inflightCall struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+// taskGroup: 리소스 다운로드를 병렬로 실행하고, 모든 작업이 끝난 뒤 결과 반영(DOM/CSS 수정)을
+// 호출한 고루틴에서 일괄 적용합니다. 실제 동시 다운로드 수는 Mirror.sem이 제한합니다.
+type taskGroup struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	applies []func()
+	afters  []func() // 모든 결과 반영이 끝난 뒤 실행 (After)
+}
+
+// Go: fn을 별도 고루틴에서 실행합니다. fn이 반환한 함수는 Wait 시점에 Go를 호출한 순서대로 실행됩니다.
+// (완료 순서와 관계없이 결과 반영 순서가 일정하므로 실행할 때마다 같은 출력이 나옴)
+func (t *taskGroup) Go(fn func() func()) {
+	t.mu.Lock()
+	slot := len(t.applies)
+	t.applies = append(t.applies, nil)
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		apply := fn()
+		t.mu.Lock()
+		t.applies[slot] = apply
+		t.mu.Unlock()
+	}()
+}
+
+// After: Wait에서 모든 결과 반영이 끝난 뒤 실행할 함수를 등록합니다. (결과 반영 함수 안에서 호출)
+// 속성이나 요소 제거처럼 다른 결과 반영이 수정할 대상을 바꾸는 작업에 사용합니다.
+func (t *taskGroup) After(fn func()) { t.afters = append(t.afters, fn) }
+
+// Wait: 모든 작업의 완료를 기다린 뒤 결과 반영 함수를 등록 순서대로 실행하고, 이어서 After 함수를 실행합니다.
+func (t *taskGroup) Wait() {
+	t.wg.Wait()
+	for _, apply := range t.applies {
+		if apply != nil { apply() }
+	}
+	for _, fn := range t.afters { fn() }
+	t.applies, t.afters = nil, nil
+}
@@ -0,0 +1,21 @@
+package localizer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskGroupAppliesInQueueOrder(t *testing.T) {
+	var tasks taskGroup
+	var got []int
+	for i := 0; i < 5; i++ {
+		tasks.Go(func() func() {
+			time.Sleep(time.Duration(5-i) * time.Millisecond) // 나중에 등록한 작업이 먼저 끝남
+			if i == 2 { return nil }
+			return func() { got = append(got, i) }
+		})
+	}
+	tasks.Wait()
+	if want := []int{0, 1, 3, 4}; !reflect.DeepEqual(got, want) { t.Errorf("applied %v, want %v", got, want) }
+}
@@ -0,0 +1,102 @@
+package localizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ==========================================
+// [시간 제한 설정 (Timeouts)]
+// ==========================================
+
+// Timeouts: 작업 단계별 시간 제한입니다.
+// 각 값이 0이면 DefaultTimeouts의 값을, 음수이면 제한 없음(Settle은 대기 없음)을 의미합니다.
+type Timeouts struct {
+	Total   time.Duration // 전체 작업 제한 (Prefetch 또는 Run 최초 호출 시점부터)
+	Request time.Duration // 개별 리소스 HTTP 요청 제한
+	Render  time.Duration // 페이지별 브라우저 렌더링 제한
+	Settle  time.Duration // 페이지 로드 후 DOM 구성을 기다리는 시간
+}
+
+// DefaultTimeouts: Timeouts 필드가 0일 때 사용되는 기본값
+var DefaultTimeouts = Timeouts{
+	Total:   60 * time.Second,
+	Request: 30 * time.Second,
+	Render:  30 * time.Second,
+	Settle:  5 * time.Second,
+}
+
+// normalize: 0은 기본값으로, 음수는 0(제한 없음)으로 변환합니다.
+func (t Timeouts) normalize() Timeouts {
+	pick := func(v, def time.Duration) time.Duration {
+		if v == 0 { return def }
+		if v < 0 { return 0 }
+		return v
+	}
+	return Timeouts{
+		Total:   pick(t.Total, DefaultTimeouts.Total),
+		Request: pick(t.Request, DefaultTimeouts.Request),
+		Render:  pick(t.Render, DefaultTimeouts.Render),
+		Settle:  pick(t.Settle, DefaultTimeouts.Settle),
+	}
+}
+
+// Budget: 초과된 시간 제한의 종류
+type Budget string
+
+const (
+	BudgetTotal   Budget = "전체 작업"
+	BudgetRequest Budget = "리소스 요청"
+	BudgetRender  Budget = "페이지 렌더링"
+)
+
+// TimeoutError: 어떤 시간 제한을 초과했는지 알려주는 오류입니다.
+// errors.Is(err, context.DeadlineExceeded) 도 true를 반환합니다.
+type TimeoutError struct {
+	Budget Budget
+	Limit  time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s 시간 제한 초과 (%s)", e.Budget, e.Limit)
+}
+
+func (e *TimeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// withBudget: 전체 작업 시간 제한을 적용한 컨텍스트를 반환합니다.
+// Prefetch와 Run이 같은 마감 시각을 공유하도록 최초 호출 시각을 기준으로 계산합니다.
+func (m *Mirror) withBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	total := m.opts.Timeouts.Total
+	if total <= 0 { return context.WithCancel(ctx) }
+	m.startOnce.Do(func() { m.startedAt = time.Now() })
+	return context.WithDeadlineCause(ctx, m.startedAt.Add(total), &TimeoutError{Budget: BudgetTotal, Limit: total})
+}
+
+// timeoutCause: 컨텍스트 마감으로 실패한 경우, 원인이 된 TimeoutError로 바꿔 반환합니다.
+func timeoutCause(ctx context.Context, err error) error {
+	if err == nil { return nil }
+	var te *TimeoutError
+	if errors.As(err, &te) { return err }
+	if errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+		if errors.As(context.Cause(ctx), &te) { return te }
+	}
+	return err
+}
+
+// requestTimeout: HTTP 클라이언트의 요청 제한 초과 오류를 TimeoutError로 변환합니다.
+func (m *Mirror) requestTimeout(ctx context.Context, err error) error {
+	var ne net.Error
+	if ctx.Err() == nil && errors.As(err, &ne) && ne.Timeout() {
+		return &TimeoutError{Budget: BudgetRequest, Limit: m.opts.Timeouts.Request}
+	}
+	return timeoutCause(ctx, err)
+}
+
+// afterOrNever: d가 0이면 영원히 오지 않는 채널을 반환합니다. (제한 없음)
+func afterOrNever(d time.Duration) <-chan time.Time {
+	if d <= 0 { return nil }
+	return time.After(d)
+}
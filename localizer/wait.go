@@ -0,0 +1,197 @@
+package localizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ==========================================
+// [렌더링 완료 감지 (Wait Strategy)]
+// ==========================================
+
+// WaitMode: 렌더링 완료로 판단할 조건입니다.
+type WaitMode string
+
+const (
+	WaitSleep            WaitMode = "sleep"            // load 이벤트 후 Timeouts.Settle 만큼 고정 대기 (기본값, 대체 수단)
+	WaitLoad             WaitMode = "load"             // load 이벤트
+	WaitDOMContentLoaded WaitMode = "domcontentloaded" // DOMContentLoaded 이벤트
+	WaitNetworkIdle      WaitMode = "networkidle"      // 진행 중인 네트워크 요청이 Wait.Idle 동안 없음
+	WaitSelector         WaitMode = "selector"         // Wait.Selector 에 해당하는 요소가 DOM에 나타남
+	WaitJS               WaitMode = "js"               // Wait.JS 표현식이 참(truthy)이 됨
+)
+
+// DefaultIdle: networkidle 조건의 기본 무요청 유지 시간
+const DefaultIdle = 500 * time.Millisecond
+
+// Wait: 페이지 렌더링 후 HTML을 가져오기 전의 대기 방식입니다.
+// Modes의 조건을 순서대로 모두 기다리며, 비어 있으면 WaitSleep(고정 대기)을 사용합니다.
+type Wait struct {
+	Modes    []WaitMode
+	Selector string        // WaitSelector 에서 기다릴 CSS 선택자
+	JS       string        // WaitJS 에서 참이 되기를 기다릴 JavaScript 표현식
+	Idle     time.Duration // WaitNetworkIdle 에서 요청이 없어야 하는 시간 (0이면 DefaultIdle)
+	Max      time.Duration // 조건별 최대 대기 시간 (0이면 렌더링 제한까지). 초과 시 현재 상태로 캡처
+}
+
+// ParseWaitModes: 쉼표로 구분된 조건 목록을 WaitMode 목록으로 변환합니다.
+func ParseWaitModes(s string) ([]WaitMode, error) {
+	var modes []WaitMode
+	for _, item := range strings.Split(s, ",") {
+		mode := WaitMode(strings.ToLower(strings.TrimSpace(item)))
+		switch mode {
+		case "":
+			continue
+		case WaitSleep, WaitLoad, WaitDOMContentLoaded, WaitNetworkIdle, WaitSelector, WaitJS:
+			modes = append(modes, mode)
+		default:
+			return nil, fmt.Errorf("알 수 없는 대기 조건입니다: %s", item)
+		}
+	}
+	return modes, nil
+}
+
+// validate: 조건에 필요한 값이 빠지지 않았는지 확인하고 기본값을 채웁니다.
+func (w Wait) validate() (Wait, error) {
+	if len(w.Modes) == 0 { w.Modes = []WaitMode{WaitSleep} }
+	if w.Idle <= 0 { w.Idle = DefaultIdle }
+	for _, mode := range w.Modes {
+		if mode == WaitSelector && w.Selector == "" { return w, fmt.Errorf("selector 대기 조건에는 CSS 선택자가 필요합니다") }
+		if mode == WaitJS && w.JS == "" { return w, fmt.Errorf("js 대기 조건에는 JavaScript 표현식이 필요합니다") }
+	}
+	return w, nil
+}
+
+// has: 해당 조건이 포함되어 있는지 확인합니다.
+func (w Wait) has(mode WaitMode) bool {
+	for _, m := range w.Modes {
+		if m == mode { return true }
+	}
+	return false
+}
+
+// pageEvents: CDP 이벤트로 페이지 로딩 상태와 진행 중인 네트워크 요청을 추적합니다.
+type pageEvents struct {
+	mu           sync.Mutex
+	inflight     map[network.RequestID]bool
+	lastActivity time.Time
+	domReady     chan struct{}
+	loaded       chan struct{}
+	domOnce      sync.Once
+	loadOnce     sync.Once
+}
+
+func newPageEvents() *pageEvents {
+	return &pageEvents{
+		inflight:     make(map[network.RequestID]bool),
+		lastActivity: time.Now(),
+		domReady:     make(chan struct{}),
+		loaded:       make(chan struct{}),
+	}
+}
+
+// handle: chromedp.ListenTarget 에 등록되는 이벤트 처리 함수
+func (e *pageEvents) handle(ev any) {
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		e.mu.Lock()
+		e.inflight[ev.RequestID] = true
+		e.lastActivity = time.Now()
+		e.mu.Unlock()
+	case *network.EventLoadingFinished:
+		e.finish(ev.RequestID)
+	case *network.EventLoadingFailed:
+		e.finish(ev.RequestID)
+	case *page.EventDomContentEventFired:
+		e.domOnce.Do(func() { close(e.domReady) })
+	case *page.EventLoadEventFired:
+		e.loadOnce.Do(func() { close(e.loaded) })
+	}
+}
+
+func (e *pageEvents) finish(id network.RequestID) {
+	e.mu.Lock()
+	delete(e.inflight, id)
+	e.lastActivity = time.Now()
+	e.mu.Unlock()
+}
+
+// waitIdle: 진행 중인 요청이 없는 상태가 idle 동안 유지될 때까지 기다립니다.
+func (e *pageEvents) waitIdle(ctx context.Context, idle time.Duration) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		e.mu.Lock()
+		quiet := len(e.inflight) == 0 && time.Since(e.lastActivity) >= idle
+		e.mu.Unlock()
+		if quiet { return nil }
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// navigateAction: DOMContentLoaded만 기다리는 경우 load 이벤트를 기다리지 않고 이동합니다.
+func (m *Mirror) navigateAction(urlStr string) chromedp.Action {
+	w := m.opts.Wait
+	if w.has(WaitDOMContentLoaded) && !w.has(WaitLoad) && !w.has(WaitSleep) {
+		return chromedp.ActionFunc(func(ctx context.Context) error {
+			_, _, errorText, _, err := page.Navigate(urlStr).Do(ctx)
+			if err != nil { return err }
+			if errorText != "" { return fmt.Errorf("page load error %s", errorText) }
+			return nil
+		})
+	}
+	return chromedp.Navigate(urlStr)
+}
+
+// waitRendered: 설정된 조건을 순서대로 기다립니다. 조건이 Wait.Max 안에 충족되지 않으면
+// 경고만 출력하고 현재 상태로 캡처를 진행합니다. 렌더링 컨텍스트 자체가 끝나면 오류를 반환합니다.
+func (m *Mirror) waitRendered(ctx context.Context, events *pageEvents, urlStr string) error {
+	w := m.opts.Wait
+	for _, mode := range w.Modes {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if w.Max > 0 { waitCtx, cancel = context.WithTimeout(ctx, w.Max) }
+
+		var err error
+		switch mode {
+		case WaitSleep:
+			if m.opts.Timeouts.Settle > 0 { err = chromedp.Run(waitCtx, chromedp.Sleep(m.opts.Timeouts.Settle)) }
+		case WaitLoad:
+			err = waitChan(waitCtx, events.loaded)
+		case WaitDOMContentLoaded:
+			err = waitChan(waitCtx, events.domReady)
+		case WaitNetworkIdle:
+			err = events.waitIdle(waitCtx, w.Idle)
+		case WaitSelector:
+			err = chromedp.Run(waitCtx, chromedp.WaitReady(w.Selector, chromedp.ByQuery))
+		case WaitJS:
+			err = chromedp.Run(waitCtx, chromedp.Poll(w.JS, nil, chromedp.WithPollingInterval(100*time.Millisecond), chromedp.WithPollingTimeout(0)))
+		}
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil { return ctx.Err() }
+			m.logf(" ⚠️  대기 조건 미충족 (%s, %s): 현재 상태로 캡처합니다 - %v\n", mode, urlStr, err)
+		}
+	}
+	return nil
+}
+
+func waitChan(ctx context.Context, ch <-chan struct{}) error {
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
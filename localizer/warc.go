@@ -0,0 +1,405 @@
+package localizer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==========================================
+// [WARC 1.1 / WACZ 아카이브 기록]
+// ==========================================
+
+// httpExchange: 아카이브에 기록할 HTTP 요청/응답 한 쌍입니다.
+type httpExchange struct {
+	URL            string
+	Method         string
+	RequestHeader  http.Header
+	Status         int
+	StatusText     string
+	ResponseHeader http.Header
+	Body           []byte // 압축이 해제된 응답 본문
+}
+
+// cdxEntry: WACZ 인덱스(CDXJ)의 한 줄
+type cdxEntry struct {
+	key  string // SURT 정렬 키
+	ts   string // 14자리 타임스탬프
+	json []byte
+}
+
+// waczPage: WACZ pages.jsonl 의 한 페이지
+type waczPage struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	TS    string `json:"ts"`
+	Title string `json:"title,omitempty"`
+}
+
+// warcWriter: HTTP 요청/응답을 WARC 1.1 레코드로 기록합니다.
+// 파일은 첫 레코드를 쓸 때 생성되며, .gz 로 끝나거나 WACZ로 묶는 경우 레코드마다 gzip 멤버로 압축합니다.
+// 모든 메서드는 nil 수신자에서도 안전하게 호출할 수 있습니다. (아카이브 미사용)
+type warcWriter struct {
+	path     string // 최종 출력 경로 (.warc, .warc.gz, .wacz)
+	wacz     bool
+	compress bool
+
+	mu        sync.Mutex
+	f         *os.File
+	offset    int64
+	err       error // 처음 발생한 기록 오류 (finish에서 반환)
+	done      bool
+	index     []cdxEntry
+	pages     []waczPage
+	seenPages map[string]bool
+}
+
+// newWarcWriter: 확장자로 형식을 결정합니다. (.wacz: WACZ 패키지, .gz: 압축 WARC, 그 외: 비압축 WARC)
+func newWarcWriter(path string) *warcWriter {
+	lower := strings.ToLower(path)
+	wacz := strings.HasSuffix(lower, ".wacz")
+	return &warcWriter{
+		path:      path,
+		wacz:      wacz,
+		compress:  wacz || strings.HasSuffix(lower, ".gz"),
+		seenPages: make(map[string]bool),
+	}
+}
+
+// recordResponse: Go HTTP 클라이언트로 받은 응답(과 그 요청)을 기록합니다.
+func (w *warcWriter) recordResponse(resp *http.Response, body []byte) {
+	if w == nil { return }
+	req := resp.Request
+	reqHeader := req.Header.Clone()
+	if reqHeader.Get("Accept-Encoding") == "" { reqHeader.Set("Accept-Encoding", "gzip") } // Transport가 자동으로 추가하는 헤더
+	w.record(httpExchange{
+		URL:            req.URL.String(),
+		Method:         req.Method,
+		RequestHeader:  reqHeader,
+		Status:         resp.StatusCode,
+		StatusText:     strings.TrimSpace(strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode))),
+		ResponseHeader: resp.Header,
+		Body:           body,
+	})
+}
+
+// recordCaptured: 브라우저 렌더링 중 캡처한 응답(리다이렉트 포함)을 기록합니다.
+func (w *warcWriter) recordCaptured(responses []*capturedResponse) {
+	if w == nil { return }
+	for _, rec := range responses { w.record(rec.exchange()) }
+}
+
+// addPage: WACZ 페이지 목록에 렌더링한 페이지를 추가합니다.
+func (w *warcWriter) addPage(pageURL string, title string) {
+	if w == nil { return }
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.seenPages[pageURL] { return }
+	w.seenPages[pageURL] = true
+	w.pages = append(w.pages, waczPage{
+		ID:    newRecordID()[len("urn:uuid:"):],
+		URL:   pageURL,
+		TS:    time.Now().UTC().Format(time.RFC3339),
+		Title: title,
+	})
+}
+
+// record: 응답 레코드와 요청 레코드를 순서대로 기록합니다.
+func (w *warcWriter) record(ex httpExchange) {
+	u, err := url.Parse(ex.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") { return }
+	now := time.Now().UTC()
+	date := now.Format("2006-01-02T15:04:05Z")
+
+	// 응답 블록: 본문은 압축이 해제된 상태이므로 인코딩 관련 헤더를 정리하고 길이를 다시 계산
+	var respBlock bytes.Buffer
+	statusText := ex.StatusText
+	if statusText == "" { statusText = http.StatusText(ex.Status) }
+	fmt.Fprintf(&respBlock, "HTTP/1.1 %d %s\r\n", ex.Status, statusText)
+	writeHTTPHeader(&respBlock, ex.ResponseHeader, "Content-Encoding", "Transfer-Encoding", "Content-Length")
+	fmt.Fprintf(&respBlock, "Content-Length: %d\r\n\r\n", len(ex.Body))
+	respBlock.Write(ex.Body)
+
+	var reqBlock bytes.Buffer
+	method := ex.Method
+	if method == "" { method = "GET" }
+	fmt.Fprintf(&reqBlock, "%s %s HTTP/1.1\r\nHost: %s\r\n", method, u.RequestURI(), u.Host)
+	writeHTTPHeader(&reqBlock, ex.RequestHeader, "Host")
+	reqBlock.WriteString("\r\n")
+
+	payloadDigest := warcDigest(ex.Body)
+	respID := newRecordID()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done { return }
+	offset, length := w.writeRecord([][2]string{
+		{"WARC-Type", "response"},
+		{"WARC-Record-ID", respID},
+		{"WARC-Date", date},
+		{"WARC-Target-URI", ex.URL},
+		{"WARC-Payload-Digest", payloadDigest},
+		{"WARC-Block-Digest", warcDigest(respBlock.Bytes())},
+		{"Content-Type", "application/http;msgtype=response"},
+	}, respBlock.Bytes())
+	w.writeRecord([][2]string{
+		{"WARC-Type", "request"},
+		{"WARC-Record-ID", newRecordID()},
+		{"WARC-Date", date},
+		{"WARC-Target-URI", ex.URL},
+		{"WARC-Concurrent-To", respID},
+		{"WARC-Block-Digest", warcDigest(reqBlock.Bytes())},
+		{"Content-Type", "application/http;msgtype=request"},
+	}, reqBlock.Bytes())
+
+	if w.wacz && w.err == nil {
+		mimeType := ex.ResponseHeader.Get("Content-Type")
+		if i := strings.IndexByte(mimeType, ';'); i != -1 { mimeType = mimeType[:i] }
+		data, _ := json.Marshal(map[string]string{
+			"url":      ex.URL,
+			"mime":     strings.TrimSpace(mimeType),
+			"status":   strconv.Itoa(ex.Status),
+			"digest":   payloadDigest,
+			"length":   strconv.FormatInt(length, 10),
+			"offset":   strconv.FormatInt(offset, 10),
+			"filename": "data.warc.gz",
+		})
+		w.index = append(w.index, cdxEntry{key: surtKey(u), ts: now.Format("20060102150405"), json: data})
+	}
+}
+
+// writeRecord: WARC 레코드 하나를 파일에 씁니다. (w.mu를 잡은 상태에서 호출)
+// 레코드의 파일 내 위치와 (압축된) 길이를 반환합니다.
+func (w *warcWriter) writeRecord(fields [][2]string, block []byte) (offset int64, length int64) {
+	if w.err != nil { return 0, 0 }
+	if w.f == nil {
+		if w.err = w.open(); w.err != nil { return 0, 0 }
+	}
+
+	var rec bytes.Buffer
+	rec.WriteString("WARC/1.1\r\n")
+	for _, f := range fields { fmt.Fprintf(&rec, "%s: %s\r\n", f[0], f[1]) }
+	fmt.Fprintf(&rec, "Content-Length: %d\r\n\r\n", len(block))
+	rec.Write(block)
+	rec.WriteString("\r\n\r\n")
+
+	data := rec.Bytes()
+	if w.compress {
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		zw.Write(data)
+		zw.Close()
+		data = gz.Bytes()
+	}
+	offset = w.offset
+	if _, w.err = w.f.Write(data); w.err != nil { return 0, 0 }
+	w.offset += int64(len(data))
+	return offset, int64(len(data))
+}
+
+// open: 출력 파일을 만들고 warcinfo 레코드를 기록합니다. WACZ는 임시 WARC에 먼저 기록합니다.
+func (w *warcWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil { return err }
+	var err error
+	if w.wacz {
+		w.f, err = os.CreateTemp(filepath.Dir(w.path), ".localizer-*.warc.gz")
+	} else {
+		w.f, err = os.Create(w.path)
+	}
+	if err != nil { return err }
+
+	info := "software: localizer\r\nformat: WARC File Format 1.1\r\nconformsTo: https://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n"
+	w.writeRecord([][2]string{
+		{"WARC-Type", "warcinfo"},
+		{"WARC-Record-ID", newRecordID()},
+		{"WARC-Date", time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+		{"WARC-Filename", filepath.Base(w.path)},
+		{"Content-Type", "application/warc-fields"},
+	}, []byte(info))
+	return w.err
+}
+
+// finish: 기록을 마치고 파일을 닫습니다. WACZ이면 WARC, 인덱스, 페이지 목록을 하나의 패키지로 묶습니다.
+// 기록된 레코드가 없어도 warcinfo만 담은 아카이브를 생성합니다.
+func (w *warcWriter) finish() error {
+	if w == nil { return nil }
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done { return w.err }
+	w.done = true
+	if w.f == nil && w.err == nil { w.err = w.open() }
+	if w.f == nil { return w.err }
+
+	if err := w.f.Close(); err != nil && w.err == nil { w.err = err }
+	if w.wacz {
+		if w.err == nil { w.err = w.packageWACZ() }
+		os.Remove(w.f.Name())
+	}
+	if w.err != nil { return fmt.Errorf("아카이브 기록 실패: %w", w.err) }
+	return nil
+}
+
+// abort: Run 없이 종료하는 경우 열려 있는 파일을 정리합니다.
+func (w *warcWriter) abort() {
+	if w == nil { return }
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done { return }
+	w.done = true
+	if w.f == nil { return }
+	w.f.Close()
+	if w.wacz { os.Remove(w.f.Name()) }
+}
+
+// packageWACZ: WACZ 1.1.1 구조로 패키징합니다.
+// (archive/data.warc.gz, indexes/index.cdx, pages/pages.jsonl, datapackage.json)
+func (w *warcWriter) packageWACZ() error {
+	sort.SliceStable(w.index, func(i, j int) bool {
+		if w.index[i].key != w.index[j].key { return w.index[i].key < w.index[j].key }
+		return w.index[i].ts < w.index[j].ts
+	})
+	var cdx bytes.Buffer
+	for _, e := range w.index { fmt.Fprintf(&cdx, "%s %s %s\n", e.key, e.ts, e.json) }
+
+	var pages bytes.Buffer
+	pages.WriteString(`{"format":"json-pages-1.0","id":"pages","title":"All Pages"}` + "\n")
+	for _, p := range w.pages {
+		line, _ := json.Marshal(p)
+		pages.Write(line)
+		pages.WriteByte('\n')
+	}
+
+	out, err := os.Create(w.path)
+	if err != nil { return err }
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	now := time.Now()
+
+	type resource struct {
+		Name  string `json:"name"`
+		Path  string `json:"path"`
+		Hash  string `json:"hash"`
+		Bytes int64  `json:"bytes"`
+	}
+	var resources []resource
+
+	// WARC는 임의 위치 접근이 가능하도록 압축 없이(Store) 저장
+	warcFile, err := os.Open(w.f.Name())
+	if err != nil { return err }
+	defer warcFile.Close()
+	entry, err := zw.CreateHeader(&zip.FileHeader{Name: "archive/data.warc.gz", Method: zip.Store, Modified: now})
+	if err != nil { return err }
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(entry, hash), warcFile)
+	if err != nil { return err }
+	resources = append(resources, resource{"data.warc.gz", "archive/data.warc.gz", "sha256:" + hex.EncodeToString(hash.Sum(nil)), n})
+
+	addFile := func(path string, data []byte) error {
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: path, Method: zip.Deflate, Modified: now})
+		if err != nil { return err }
+		if _, err := entry.Write(data); err != nil { return err }
+		sum := sha256.Sum256(data)
+		resources = append(resources, resource{filepath.Base(path), path, "sha256:" + hex.EncodeToString(sum[:]), int64(len(data))})
+		return nil
+	}
+	if err := addFile("indexes/index.cdx", cdx.Bytes()); err != nil { return err }
+	if err := addFile("pages/pages.jsonl", pages.Bytes()); err != nil { return err }
+
+	pkg, _ := json.MarshalIndent(map[string]any{
+		"profile":      "data-package",
+		"wacz_version": "1.1.1",
+		"created":      now.UTC().Format(time.RFC3339),
+		"software":     "localizer",
+		"resources":    resources,
+	}, "", "  ")
+	entry, err = zw.CreateHeader(&zip.FileHeader{Name: "datapackage.json", Method: zip.Deflate, Modified: now})
+	if err != nil { return err }
+	if _, err := entry.Write(pkg); err != nil { return err }
+	return zw.Close()
+}
+
+// exchange: 캡처된 브라우저 응답을 기록용 요청/응답 쌍으로 변환합니다.
+func (rec *capturedResponse) exchange() httpExchange {
+	return httpExchange{
+		URL:            rec.URL,
+		Method:         rec.Method,
+		RequestHeader:  cdpHeader(rec.RequestHeaders),
+		Status:         rec.Status,
+		StatusText:     rec.StatusText,
+		ResponseHeader: cdpHeader(rec.ResponseHeaders),
+		Body:           rec.Body,
+	}
+}
+
+// cdpHeader: CDP 헤더 맵을 http.Header로 변환합니다. (여러 값은 줄바꿈으로 이어져 있음)
+func cdpHeader(h map[string]any) http.Header {
+	header := make(http.Header)
+	for key, val := range h {
+		if strings.HasPrefix(key, ":") { continue } // HTTP/2 의사 헤더 제외
+		for _, v := range strings.Split(fmt.Sprint(val), "\n") { header.Add(key, v) }
+	}
+	return header
+}
+
+// writeHTTPHeader: 헤더를 이름순으로 기록합니다. skip에 나열된 헤더는 제외합니다.
+func writeHTTPHeader(buf *bytes.Buffer, header http.Header, skip ...string) {
+	keys := make([]string, 0, len(header))
+	for key := range header { keys = append(keys, key) }
+	sort.Strings(keys)
+	for _, key := range keys {
+		skipped := false
+		for _, s := range skip {
+			if strings.EqualFold(key, s) { skipped = true }
+		}
+		if skipped { continue }
+		for _, v := range header[key] { fmt.Fprintf(buf, "%s: %s\r\n", key, v) }
+	}
+}
+
+// warcDigest: WARC 표준 형식의 SHA-1 다이제스트 (sha1:BASE32)
+func warcDigest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// newRecordID: WARC-Record-ID 로 사용할 무작위 UUID (v4)
+func newRecordID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// surtKey: CDXJ 정렬 키 (예: https://www.example.com/a?b=1 -> com,example)/a?b=1)
+func surtKey(u *url.URL) string {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	parts := strings.Split(host, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 { parts[i], parts[j] = parts[j], parts[i] }
+	key := strings.Join(parts, ",")
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		key += ":" + port
+	}
+	p := u.EscapedPath()
+	if p == "" { p = "/" }
+	key += ")" + strings.ToLower(p)
+	if u.RawQuery != "" { key += "?" + strings.ToLower(u.Query().Encode()) }
+	return key
+}
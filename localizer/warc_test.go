@@ -0,0 +1,156 @@
+package localizer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// warcRecord: 테스트에서 다시 읽은 WARC 레코드
+type warcRecord struct {
+	header textproto.MIMEHeader
+	block  []byte
+}
+
+// readWarcRecords: WARC 파일을 레코드 단위로 읽으며 레코드 구분(Content-Length 뒤 CRLF CRLF)을 검사합니다.
+func readWarcRecords(t *testing.T, r io.Reader) []warcRecord {
+	t.Helper()
+	br := bufio.NewReader(r)
+	var records []warcRecord
+	for {
+		version, err := br.ReadString('\n')
+		if err == io.EOF && version == "" { return records }
+		if version != "WARC/1.1\r\n" { t.Fatalf("record %d: version line %q", len(records), version) }
+		header, err := textproto.NewReader(br).ReadMIMEHeader()
+		if err != nil { t.Fatalf("record %d: header: %v", len(records), err) }
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil { t.Fatalf("record %d: Content-Length %q", len(records), header.Get("Content-Length")) }
+		block := make([]byte, length)
+		if _, err := io.ReadFull(br, block); err != nil { t.Fatalf("record %d: block: %v", len(records), err) }
+		sep := make([]byte, 4)
+		if _, err := io.ReadFull(br, sep); err != nil || string(sep) != "\r\n\r\n" {
+			t.Fatalf("record %d: separator %q, %v", len(records), sep, err)
+		}
+		records = append(records, warcRecord{header, block})
+	}
+}
+
+// testExchange: 압축 전송된 것처럼 헤더를 가진 응답 (기록 시 Content-Encoding/Content-Length 제거 대상)
+func testExchange(u string, body string) httpExchange {
+	return httpExchange{
+		URL:            u,
+		RequestHeader:  http.Header{"Accept": {"*/*"}},
+		Status:         200,
+		ResponseHeader: http.Header{"Content-Type": {"text/html; charset=utf-8"}, "Content-Encoding": {"gzip"}, "Content-Length": {"3"}},
+		Body:           []byte(body),
+	}
+}
+
+func TestWarcRecordFraming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.warc")
+	w := newWarcWriter(path)
+	body := "<html>\r\n\r\nbody with blank lines</html>"
+	w.record(testExchange("https://example.com/a?b=1", body))
+	w.record(httpExchange{URL: "data:text/plain,x"}) // http(s)가 아니면 기록하지 않음
+	if err := w.finish(); err != nil { t.Fatal(err) }
+
+	f, err := os.Open(path)
+	if err != nil { t.Fatal(err) }
+	defer f.Close()
+	records := readWarcRecords(t, f)
+	if len(records) != 3 { t.Fatalf("got %d records, want warcinfo + response + request", len(records)) }
+
+	wantTypes := []string{"warcinfo", "response", "request"}
+	for i, rec := range records {
+		if got := rec.header.Get("WARC-Type"); got != wantTypes[i] { t.Errorf("record %d: WARC-Type %q, want %q", i, got, wantTypes[i]) }
+		if got := rec.header.Get("WARC-Block-Digest"); i > 0 && got != warcDigest(rec.block) { t.Errorf("record %d: block digest %q", i, got) }
+	}
+
+	resp, req := records[1], records[2]
+	if got := resp.header.Get("WARC-Target-URI"); got != "https://example.com/a?b=1" { t.Errorf("target URI %q", got) }
+	if req.header.Get("WARC-Concurrent-To") != resp.header.Get("WARC-Record-ID") { t.Error("request record is not linked to its response") }
+
+	// 응답 블록: HTTP 헤더 뒤의 페이로드가 본문과 같고, 인코딩 헤더는 정리되어 길이가 다시 계산됨
+	head, payload, ok := bytes.Cut(resp.block, []byte("\r\n\r\n"))
+	if !ok { t.Fatal("response block has no header terminator") }
+	if string(payload) != body { t.Errorf("payload %q, want %q", payload, body) }
+	if got := resp.header.Get("WARC-Payload-Digest"); got != warcDigest([]byte(body)) { t.Errorf("payload digest %q", got) }
+	wantHead := "HTTP/1.1 200 OK\r\nContent-Type: text/html; charset=utf-8\r\nContent-Length: " + strconv.Itoa(len(body))
+	if string(head) != wantHead { t.Errorf("response head:\n got %q\nwant %q", head, wantHead) }
+
+	wantReq := "GET /a?b=1 HTTP/1.1\r\nHost: example.com\r\nAccept: */*\r\n\r\n"
+	if string(req.block) != wantReq { t.Errorf("request block:\n got %q\nwant %q", req.block, wantReq) }
+}
+
+func TestWaczIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wacz")
+	w := newWarcWriter(path)
+	w.record(testExchange("https://www.example.com/z", "z"))
+	w.record(testExchange("http://example.org:8080/a", "a"))
+	w.record(testExchange("https://example.com/b", "b"))
+	w.addPage("https://example.com/b", "B")
+	w.addPage("https://example.com/b", "B") // 같은 페이지는 한 번만
+	if err := w.finish(); err != nil { t.Fatal(err) }
+
+	zr, err := zip.OpenReader(path)
+	if err != nil { t.Fatal(err) }
+	defer zr.Close()
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File { files[f.Name] = f }
+	readEntry := func(name string) []byte {
+		f, ok := files[name]
+		if !ok { t.Fatalf("missing %s", name) }
+		rc, err := f.Open()
+		if err != nil { t.Fatal(err) }
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil { t.Fatal(err) }
+		return data
+	}
+	if files["archive/data.warc.gz"].Method != zip.Store { t.Error("archive/data.warc.gz must be stored uncompressed for random access") }
+	warcData := readEntry("archive/data.warc.gz")
+
+	// 인덱스는 SURT 키 순서이며, offset/length가 가리키는 gzip 멤버가 해당 URL의 응답 레코드
+	lines := strings.Split(strings.TrimSpace(string(readEntry("indexes/index.cdx"))), "\n")
+	wantKeys := []string{"com,example)/b", "com,example)/z", "org,example:8080)/a"}
+	if len(lines) != len(wantKeys) { t.Fatalf("index has %d lines, want %d:\n%s", len(lines), len(wantKeys), strings.Join(lines, "\n")) }
+	for i, line := range lines {
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 { t.Fatalf("index line %q", line) }
+		if fields[0] != wantKeys[i] { t.Errorf("index line %d: key %q, want %q", i, fields[0], wantKeys[i]) }
+		if len(fields[1]) != 14 { t.Errorf("index line %d: timestamp %q", i, fields[1]) }
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(fields[2]), &entry); err != nil { t.Fatal(err) }
+		if entry["mime"] != "text/html" || entry["status"] != "200" || entry["filename"] != "data.warc.gz" { t.Errorf("index line %d: %v", i, entry) }
+
+		offset, _ := strconv.Atoi(entry["offset"])
+		length, _ := strconv.Atoi(entry["length"])
+		if offset+length > len(warcData) { t.Fatalf("index line %d: offset %d + length %d beyond archive", i, offset, length) }
+		zr, err := gzip.NewReader(bytes.NewReader(warcData[offset : offset+length]))
+		if err != nil { t.Fatal(err) }
+		zr.Multistream(false)
+		records := readWarcRecords(t, zr)
+		if len(records) != 1 { t.Fatalf("index line %d: %d records at offset", i, len(records)) }
+		if got := records[0].header.Get("WARC-Target-URI"); got != entry["url"] { t.Errorf("index line %d: record for %q, index says %q", i, got, entry["url"]) }
+		if got := records[0].header.Get("WARC-Payload-Digest"); got != entry["digest"] { t.Errorf("index line %d: digest %q, index says %q", i, got, entry["digest"]) }
+	}
+
+	pages := strings.Split(strings.TrimSpace(string(readEntry("pages/pages.jsonl"))), "\n")
+	if len(pages) != 2 || !strings.Contains(pages[1], `"url":"https://example.com/b"`) { t.Errorf("pages.jsonl:\n%s", strings.Join(pages, "\n")) }
+
+	var pkg struct {
+		Resources []struct{ Path string } `json:"resources"`
+	}
+	if err := json.Unmarshal(readEntry("datapackage.json"), &pkg); err != nil { t.Fatal(err) }
+	if len(pkg.Resources) != 3 { t.Errorf("datapackage resources: %+v", pkg.Resources) }
+}
@@ -1,699 +1,608 @@
-/*
-===============================================================================================
-[프로그램 명세서 및 작동 논리 (Program Architecture & Logic Specification)]
-Designed for AI Context Understanding
-
-1. 프로그램 개요 (Overview)
-   - 이 프로그램은 정적 웹사이트(로컬 파일 시스템) 또는 원격 웹사이트(URL)의 모든 리소스를
-     재귀적으로 수집하여 로컬 환경에서 오프라인으로 열람 가능하도록 미러링(Mirroring)하는 도구입니다.
-   - 단순한 파일 다운로드가 아닌, HTML/CSS 내부의 참조 링크를 분석하여 로컬 상대 경로로 자동 변환합니다.
-
-2. 실행 모드 (Execution Modes)
-   A. 원격 모드 (Remote Mode)
-      - 조건: 입력값이 "http://" 또는 "https://"로 시작할 때.
-      - 동작: Headless Browser (Chromedp)를 사용하여 페이지를 엽니다.
-      - 특징:
-        1. 메인 스레드와 별개로 고루틴(Goroutine)이 브라우저 렌더링을 즉시 시작 (Pre-fetching).
-        2. 1920x1080 해상도로 렌더링하며, 초기 로딩 5초 대기.
-        3. 렌더링된 최종 DOM(OuterHTML)을 추출하여 파싱.
-   B. 로컬 모드 (Local Mode)
-      - 조건: 입력값이 일반 파일/폴더 경로일 때.
-      - 동작: os.ReadFile을 통해 파일을 직접 읽습니다.
-      - 특징: <script> 태그 내부의 텍스트까지 정규식으로 분석하여 동적으로 연결된 .html 파일도 추적.
-
-3. 실행 옵션 (-o Flag)
-   - "-o [경로]": 지정된 경로에 결과물을 저장합니다. (예: -o my_site)
-   - "-o ." 또는 옵션 미지정: 기본값 "front_local" 폴더에 저장합니다.
-   - 안전장치: 출력 폴더가 이미 존재할 경우, 사용자에게 삭제 여부(Y/n)를 확인합니다.
-
-4. 타임아웃 및 리소스 관리 (Safety & Constraints)
-   - Global Timeout: 전체 작업은 60초(1분)로 제한됩니다. 초과 시 작업 취소 및 경고 출력.
-   - HTTP Client: 개별 리소스 다운로드는 30초 타임아웃이 적용됩니다.
-   - Caching: 이미 다운로드된 리소스(Disk Cache)는 중복 요청하지 않고 건너뜁니다.
-
-5. 데이터 처리 파이프라인 (Processing Pipeline)
-   Step 1. 입력값 분석 (URL vs Local) 및 모드 설정.
-   Step 2. 사전 유효성 검사 (URL 접속 가능 여부 / 파일 존재 여부).
-   Step 3. 출력 디렉토리 준비 (/assets, /fonts 생성).
-   Step 4. HTML 파싱 (Golang net/html 패키지 사용).
-   Step 5. DOM 순회 -> 리소스 발견 -> 다운로드 -> 경로 재계산(filepath.Rel) -> 속성값 수정.
-   Step 6. CSS 파일인 경우, 내부의 url(...) 패턴을 찾아 재귀적으로 리소스 다운로드.
-   Step 7. 최종 파일 저장 및 통계 출력.
-
-===============================================================================================
-*/
-
-package main
-
-import (
-	"bufio"
-	"bytes"
-	"context"
-	"errors"
-	"flag"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
-	"os"
-	"path"
-	"path/filepath"
-	"regexp"
-	"strings"
-	"time"
-
-	"github.com/chromedp/chromedp"
-	"golang.org/x/net/html"
-)
-
-// ==========================================
-// [전역 설정 및 상태 변수]
-// ==========================================
-
-var (
-	RootDir   string // 작업의 기준이 되는 루트 경로 (로컬 폴더 경로 또는 웹 Base URL)
-	StartFile string // 최초 진입점이 되는 파일명 (예: index.html)
-	OutputDir string // 결과물이 저장될 최종 루트 폴더
-	AssetDir  = "assets" // JS, CSS, 이미지 저장 하위 폴더명
-	FontDir   = "fonts"  // 폰트 파일 저장 하위 폴더명
-	IsRemote  bool       // 원격 URL 크롤링 모드 여부
-)
-
-// 30초 타임아웃이 설정된 HTTP 클라이언트 (개별 리소스 요청용)
-var httpClient = &http.Client{
-	Timeout: 30 * time.Second,
-}
-
-// 고루틴 결과를 전달받기 위한 구조체
-type RenderResult struct {
-	Data []byte
-	Err  error
-}
-
-// 메인 페이지 렌더링 결과를 전달받는 채널
-var rootRenderChan chan RenderResult
-
-// 통계 집계용 변수
-var (
-	totalFiles int
-	totalBytes int64
-)
-
-// 중복 처리 방지 및 방문 기록 맵
-var processedFiles = make(map[string]string)
-var visitedHTMLs = make(map[string]bool)
-
-// ==========================================
-// [메인 실행 함수]
-// ==========================================
-func main() {
-	// 0. 프로그램 배너 출력
-	fmt.Println("===================================================")
-	fmt.Println("   JunghoKor's AI Web page local downloader v0.2")
-	fmt.Println("===================================================")
-
-	// 1. [전처리] 인자 재배열 (Flag Reordering)
-	// Go flag 패키지는 [옵션] [인자] 순서를 강제하므로, 사용자가 섞어 써도 동작하도록 재배열
-	var flagArgs []string
-	var normalArgs []string
-	for i := 1; i < len(os.Args); i++ {
-		arg := os.Args[i]
-		if strings.HasPrefix(arg, "-") {
-			// -otest 처럼 붙여쓴 경우 분리
-			if strings.HasPrefix(arg, "-o") && len(arg) > 2 && arg[2] != '=' {
-				flagArgs = append(flagArgs, "-o")
-				flagArgs = append(flagArgs, arg[2:])
-			} else {
-				flagArgs = append(flagArgs, arg)
-				// -o 뒤에 값이 바로 오면 같이 가져감
-				if arg == "-o" && i+1 < len(os.Args) && !strings.HasPrefix(os.Args[i+1], "-") {
-					flagArgs = append(flagArgs, os.Args[i+1])
-					i++
-				}
-			}
-		} else {
-			normalArgs = append(normalArgs, arg)
-		}
-	}
-	os.Args = append([]string{os.Args[0]}, append(flagArgs, normalArgs...)...)
-
-	// 2. 옵션 파싱
-	outputFlag := flag.String("o", "", "결과물이 저장될 폴더 경로")
-	flag.Parse()
-
-	// 3. 출력 폴더 결정 로직
-	if *outputFlag == "" || *outputFlag == "." {
-		OutputDir = "front_local"
-	} else {
-		OutputDir = *outputFlag
-	}
-
-	// 4. 입력값 분석 및 모드 결정
-	args := flag.Args()
-	inputArg := "front" // 기본값
-	if len(args) > 0 {
-		inputArg = args[0]
-	}
-
-	// 전체 작업에 대한 60초 타임아웃 컨텍스트 생성
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	if strings.HasPrefix(inputArg, "http://") || strings.HasPrefix(inputArg, "https://") {
-		setupRemoteMode(inputArg)
-		// 원격 모드일 경우, 메인 페이지 렌더링을 백그라운드에서 즉시 시작
-		startRemoteRendering(ctx)
-	} else {
-		setupLocalMode(inputArg)
-	}
-
-	// 5. 입력 경로 유효성 검사 (실제 접속/존재 확인)
-	if !validateInput() {
-		os.Exit(1)
-	}
-
-	// 6. 출력 폴더 생성 및 중복 확인 (사용자 동의)
-	if !checkAndPrepareOutput() {
-		return
-	}
-
-	// 7. 작업 시작
-	printStartInfo()
-	err := processHTMLFile(ctx, StartFile)
-
-	// 8. 결과 통계 출력
-	printResult(err)
-}
-
-// ==========================================
-// [설정 및 유효성 검사 함수들]
-// ==========================================
-
-// setupRemoteMode: 원격 URL을 분석하여 RootDir(Base URL)과 StartFile을 설정합니다.
-func setupRemoteMode(inputURL string) {
-	IsRemote = true
-	u, err := url.Parse(inputURL)
-	if err != nil {
-		panic("잘못된 URL입니다: " + err.Error())
-	}
-	ext := filepath.Ext(u.Path)
-	// URL이 구체적인 파일을 가리키는지 확인
-	isExplicitFile := ext != "" || (!strings.HasSuffix(u.Path, "/") && u.Path != "" && u.Path != "/")
-
-	if isExplicitFile {
-		StartFile = path.Base(u.Path)
-		u.Path = path.Dir(u.Path)
-	} else {
-		StartFile = "index.html"
-	}
-
-	// Base URL 정규화
-	if u.Path == "." || u.Path == "" { u.Path = "/" }
-	if !strings.HasSuffix(u.Path, "/") { u.Path += "/" }
-	RootDir = u.String()
-}
-
-// startRemoteRendering: 원격 URL 렌더링을 별도 고루틴에서 시작합니다. (프리페칭)
-func startRemoteRendering(ctx context.Context) {
-	rootRenderChan = make(chan RenderResult, 1)
-	u, _ := url.Parse(RootDir)
-	rel, _ := url.Parse(StartFile)
-	targetURL := u.ResolveReference(rel).String()
-
-	fmt.Println("-> 입력 URL 렌더링 시작")
-
-	go func() {
-		// fetchRenderedHTML 내부에서 30초 타임아웃 컨텍스트를 별도로 사용함
-		data, err := fetchRenderedHTML(ctx, targetURL)
-		
-		// 메인 스레드가 이미 종료되었을 경우를 대비한 select
-		select {
-		case rootRenderChan <- RenderResult{Data: data, Err: err}:
-		case <-ctx.Done():
-		}
-		close(rootRenderChan)
-	}()
-}
-
-// setupLocalMode: 로컬 파일 경로를 기준으로 설정을 초기화합니다.
-func setupLocalMode(inputPath string) {
-	IsRemote = false
-	RootDir = inputPath
-	StartFile = "index.html"
-}
-
-// validateInput: 입력된 경로가 실제로 접근 가능한지 사전 검사합니다.
-func validateInput() bool {
-	if IsRemote {
-		checkURL := RootDir
-		if StartFile != "index.html" {
-			u, _ := url.Parse(RootDir)
-			rel, _ := url.Parse(StartFile)
-			checkURL = u.ResolveReference(rel).String()
-		}
-		// 가벼운 HTTP Request로 연결 확인
-		req, _ := http.NewRequest("GET", checkURL, nil)
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)")
-		
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			fmt.Printf("❌ 오류: 원격 서버 접속 불가 (%s)\n", err)
-			return false
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode >= 400 {
-			fmt.Printf("❌ 오류: 원격 리소스 없음 (HTTP %d)\n", resp.StatusCode)
-			return false
-		}
-	} else {
-		checkPath := filepath.Join(RootDir, StartFile)
-		if _, err := os.Stat(checkPath); os.IsNotExist(err) {
-			fmt.Printf("❌ 오류: 입력 파일을 찾을 수 없음 (%s)\n", checkPath)
-			return false
-		}
-	}
-	return true
-}
-
-// checkAndPrepareOutput: 출력 폴더가 존재하면 삭제 여부를 묻고, 필요한 하위 폴더를 생성합니다.
-func checkAndPrepareOutput() bool {
-	if info, err := os.Stat(OutputDir); err == nil && info.IsDir() {
-		absPath, _ := filepath.Abs(OutputDir)
-		fmt.Printf("\n⚠️  경고: 출력 폴더가 이미 존재합니다.\n   경로: %s\n", absPath)
-		fmt.Print("   기존 폴더를 삭제하고 다시 생성하시겠습니까? (Y/n): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(strings.ToLower(input))
-
-		if input == "y" || input == "" {
-			fmt.Println("♻️  기존 폴더 삭제 중...")
-			os.RemoveAll(OutputDir)
-		} else {
-			fmt.Println("❌ 작업을 취소합니다.")
-			return false
-		}
-	}
-
-	// assets, fonts 폴더 생성
-	dirs := []string{
-		filepath.Join(OutputDir, AssetDir),
-		filepath.Join(OutputDir, FontDir),
-	}
-	for _, d := range dirs {
-		if err := os.MkdirAll(d, 0755); err != nil {
-			panic(fmt.Sprintf("폴더 생성 실패: %v", err))
-		}
-	}
-	return true
-}
-
-func printStartInfo() {
-	mode := "로컬 파일 분석"
-	if IsRemote {
-		mode = "웹 렌더링 (Headless Browser)"
-	}
-	absOut, _ := filepath.Abs(OutputDir)
-	fmt.Printf("🚀 작업 시작 (%s)\n   🔗 소스: %s (시작: %s)\n   📂 출력: %s\n", mode, RootDir, StartFile, absOut)
-	fmt.Println("==================================================")
-}
-
-func printResult(err error) {
-	fmt.Println("==================================================")
-	if err != nil {
-		// Context 타임아웃 에러인지 확인
-		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "context deadline exceeded") {
-			fmt.Printf("*** Warning : Timeout (1분 초과)\n")
-		} else {
-			fmt.Printf("❌ 오류 발생: %v\n", err)
-		}
-	} else {
-		fmt.Printf("✅ 작업 완료!\n")
-	}
-	fmt.Printf("Total %d files, saved %s bytes\n", totalFiles, formatComma(totalBytes))
-}
-
-// shouldIgnoreLink: 수집하지 말아야 할 스키마(data, mailto 등)를 필터링합니다.
-func shouldIgnoreLink(link string) bool {
-	link = strings.TrimSpace(strings.ToLower(link))
-	if link == "" { return true }
-	if strings.HasPrefix(link, "data:") ||
-		strings.HasPrefix(link, "#") ||
-		strings.HasPrefix(link, "about:") ||
-		strings.HasPrefix(link, "javascript:") ||
-		strings.HasPrefix(link, "mailto:") ||
-		strings.HasPrefix(link, "tel:") ||
-		strings.HasPrefix(link, "sms:") ||
-		strings.HasPrefix(link, "chrome:") {
-		return true
-	}
-	return false
-}
-
-// ==========================================
-// [핵심 로직 처리 함수들]
-// ==========================================
-
-// processHTMLFile: HTML 파일을 처리하는 핵심 함수. 재귀적으로 호출될 수 있습니다.
-func processHTMLFile(ctx context.Context, htmlRelPath string) error {
-	// 작업 취소 확인
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-	}
-
-	normalizedPath := filepath.ToSlash(htmlRelPath)
-	if visitedHTMLs[normalizedPath] {
-		return nil
-	}
-	visitedHTMLs[normalizedPath] = true
-
-	outputFile := filepath.Join(OutputDir, htmlRelPath)
-	localHtmlDir := filepath.Dir(outputFile)
-
-	var currentContext string
-	var content []byte
-	var err error
-
-	if IsRemote {
-		u, err := url.Parse(RootDir)
-		if err != nil { return err }
-		rel, err := url.Parse(normalizedPath)
-		if err != nil { return err }
-		targetURL := u.ResolveReference(rel).String()
-
-		currentContext = targetURL
-		if path.Ext(targetURL) != "" {
-			currentContext = path.Dir(targetURL)
-			if !strings.HasSuffix(currentContext, "/") { currentContext += "/" }
-		}
-
-		// 시작 파일인 경우, 미리 실행해둔 고루틴의 결과를 기다림
-		if htmlRelPath == StartFile && rootRenderChan != nil {
-			fmt.Println(" ⏳ 렌더링 결과 대기 중 (최대 15초)...")
-			select {
-			case result := <-rootRenderChan:
-				content, err = result.Data, result.Err
-				if err != nil { return fmt.Errorf("Background 렌더링 실패: %w", err) }
-				fmt.Println(" ✨ 렌더링 데이터 수신 완료")
-			case <-time.After(15 * time.Second):
-				return fmt.Errorf("⏳ 렌더링 시간 초과 (15초)")
-			case <-ctx.Done():
-				return ctx.Err()
-			}
-		} else {
-			// iframe 등으로 재귀 호출된 경우 동기적으로 렌더링
-			fmt.Printf(" 🖥️  브라우저 렌더링 중... (%s)\n", targetURL)
-			content, err = fetchRenderedHTML(ctx, targetURL)
-			if err != nil { return fmt.Errorf("Chrome 렌더링 실패: %w", err) }
-		}
-	} else {
-		// 로컬 파일 읽기
-		inputFile := filepath.Join(RootDir, htmlRelPath)
-		currentContext = filepath.Dir(htmlRelPath)
-		content, err = os.ReadFile(inputFile)
-	}
-
-	if err != nil { return fmt.Errorf("HTML 읽기 실패: %w", err) }
-
-	doc, err := html.Parse(bytes.NewReader(content))
-	if err != nil { return err }
-
-	displayPath := filepath.ToSlash(filepath.Join(OutputDir, htmlRelPath))
-	fmt.Printf(" 📄 %s\n", displayPath)
-
-	// DOM 순회하며 리소스 수집
-	var f func(*html.Node)
-	f = func(n *html.Node) {
-		// 루프 내에서도 타임아웃 체크
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		if n.Type == html.ElementNode {
-			if n.Data == "script" {
-				handleAttribute(ctx, n, "src", currentContext, localHtmlDir)
-				if !IsRemote { scanScriptContent(ctx, n, currentContext) }
-			}
-			if n.Data == "link" {
-				handleAttribute(ctx, n, "href", currentContext, localHtmlDir)
-			}
-			if n.Data == "img" {
-				handleAttribute(ctx, n, "src", currentContext, localHtmlDir)
-				handleAttribute(ctx, n, "data-src", currentContext, localHtmlDir)
-			}
-			if n.Data == "iframe" {
-				handleIframe(ctx, n, currentContext)
-			}
-		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			f(c)
-		}
-	}
-	f(doc)
-
-	if ctx.Err() != nil { return ctx.Err() }
-
-	// 변환된 HTML 저장
-	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil { return err }
-	var buf bytes.Buffer
-	if err := html.Render(&buf, doc); err != nil { return err }
-
-	err = os.WriteFile(outputFile, buf.Bytes(), 0644)
-	if err == nil { updateStats(int64(buf.Len())) }
-	return err
-}
-
-// fetchRenderedHTML: Chromedp를 이용하여 웹페이지를 렌더링하고 HTML을 반환합니다.
-func fetchRenderedHTML(ctx context.Context, urlStr string) ([]byte, error) {
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36"),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-	taskCtx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
-	
-	// 페이지별 최대 30초 타임아웃
-	taskCtx, cancel = context.WithTimeout(taskCtx, 30*time.Second)
-	defer cancel()
-
-	var res string
-
-	err := chromedp.Run(taskCtx,
-		chromedp.EmulateViewport(1920, 1080),
-		chromedp.Navigate(urlStr),
-		chromedp.Sleep(5*time.Second), // DOM 구성 대기
-		chromedp.OuterHTML("html", &res),
-	)
-
-	if err != nil { return nil, err }
-	return []byte(res), nil
-}
-
-// scanScriptContent: 로컬 스크립트 내의 HTML 파일 참조를 스캔합니다.
-func scanScriptContent(ctx context.Context, n *html.Node, currentBaseDir string) {
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if c.Type == html.TextNode {
-			re := regexp.MustCompile(`['"]([^'"]+\.html)['"]`)
-			matches := re.FindAllStringSubmatch(c.Data, -1)
-			for _, match := range matches {
-				if ctx.Err() != nil { return }
-				if len(match) < 2 { continue }
-				detectedFile := match[1]
-				if shouldIgnoreLink(detectedFile) { continue }
-				if strings.HasPrefix(detectedFile, "http") || strings.HasPrefix(detectedFile, "//") { continue }
-				localSrcCheck := filepath.Join(RootDir, currentBaseDir, detectedFile)
-				if _, err := os.Stat(localSrcCheck); err == nil {
-					processHTMLFile(ctx, filepath.Join(currentBaseDir, detectedFile))
-				}
-			}
-		}
-	}
-}
-
-// handleIframe: Iframe 태그를 처리합니다.
-func handleIframe(ctx context.Context, n *html.Node, currentBaseDir string) {
-	for i, a := range n.Attr {
-		if a.Key == "src" {
-			if shouldIgnoreLink(a.Val) { continue }
-			if strings.HasPrefix(a.Val, "http") || strings.HasPrefix(a.Val, "//") { continue }
-			if err := processHTMLFile(ctx, a.Val); err == nil {
-				n.Attr[i].Val = filepath.ToSlash(a.Val)
-			}
-		}
-	}
-}
-
-// handleAttribute: 일반 리소스 속성(src, href)을 처리합니다.
-func handleAttribute(ctx context.Context, n *html.Node, attrName string, currentContext string, localHtmlDir string) {
-	for i, a := range n.Attr {
-		if a.Key == attrName {
-			val := strings.TrimSpace(a.Val)
-			if shouldIgnoreLink(val) { continue }
-
-			resourceRelPath, err := downloadResource(ctx, val, currentContext)
-			if err == nil {
-				absResourcePath := filepath.Join(OutputDir, resourceRelPath)
-				relPath, err := filepath.Rel(localHtmlDir, absResourcePath)
-				if err == nil {
-					n.Attr[i].Val = filepath.ToSlash(relPath)
-				}
-			}
-		}
-	}
-}
-
-// downloadResource: 리소스를 다운로드하고 저장합니다. (중복 확인 및 캐싱 포함)
-func downloadResource(ctx context.Context, urlOrPath string, contextStr string) (string, error) {
-	if ctx.Err() != nil { return "", ctx.Err() }
-
-	var targetURL string
-	var isRemote bool
-
-	// 다운로드 대상 절대 경로 계산
-	if strings.HasPrefix(contextStr, "http") {
-		baseURL, err := url.Parse(contextStr)
-		if err != nil { return "", err }
-		relURL, err := url.Parse(urlOrPath)
-		if err != nil { return "", err }
-		targetURL = baseURL.ResolveReference(relURL).String()
-		isRemote = true
-	} else {
-		if strings.HasPrefix(urlOrPath, "http") || strings.HasPrefix(urlOrPath, "//") {
-			targetURL = urlOrPath
-			if strings.HasPrefix(urlOrPath, "//") { targetURL = "https:" + urlOrPath }
-			isRemote = true
-		} else {
-			targetURL = filepath.Join(RootDir, contextStr, urlOrPath)
-			isRemote = false
-		}
-	}
-
-	if savedRelPath, ok := processedFiles[targetURL]; ok { return savedRelPath, nil }
-
-	u, _ := url.Parse(targetURL)
-	var fileName string
-	if isRemote { fileName = path.Base(u.Path) } else { fileName = filepath.Base(targetURL) }
-
-	if idx := strings.Index(fileName, "?"); idx != -1 { fileName = fileName[:idx] }
-	if fileName == "." || fileName == "/" || fileName == "" {
-		if isRemote && u.Host != "" { fileName = u.Host + ".js" } else { fileName = "resource.bin" }
-	}
-
-	targetSubDir := AssetDir
-	if isFontFile(fileName) { targetSubDir = FontDir }
-
-	saveRelPath := filepath.Join(targetSubDir, fileName)
-	saveFullPath := filepath.Join(OutputDir, saveRelPath)
-
-	// [캐싱] 이미 존재하는 파일이면 다운로드 스킵
-	if info, err := os.Stat(saveFullPath); err == nil && !info.IsDir() {
-		processedFiles[targetURL] = saveRelPath
-		displayPath := "/" + filepath.ToSlash(filepath.Join(filepath.Base(OutputDir), saveRelPath))
-		fmt.Printf("           └── %s (Cached)\n", displayPath)
-
-		// CSS라면 내부 파싱만 다시 수행
-		if strings.HasSuffix(strings.ToLower(fileName), ".css") {
-			content, _ := os.ReadFile(saveFullPath)
-			var newContext string
-			if isRemote { newContext = targetURL } else { newContext = filepath.Dir(urlOrPath) }
-			processCSSContent(ctx, content, newContext, targetSubDir)
-		}
-		return saveRelPath, nil
-	}
-
-	var data []byte
-	var err error
-
-	if isRemote {
-		req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
-		if err != nil { return "", err }
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)")
-
-		resp, err := httpClient.Do(req)
-		if err != nil { return "", err }
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 { return "", fmt.Errorf("status %d", resp.StatusCode) }
-		data, err = io.ReadAll(resp.Body)
-	} else {
-		data, err = os.ReadFile(targetURL)
-	}
-	if err != nil { return "", err }
-
-	// CSS 파일 내부 파싱 (재귀)
-	if strings.HasSuffix(strings.ToLower(fileName), ".css") {
-		var newContext string
-		if isRemote { newContext = targetURL } else { newContext = filepath.Dir(urlOrPath) }
-		data = processCSSContent(ctx, data, newContext, targetSubDir)
-	}
-
-	if err := os.WriteFile(saveFullPath, data, 0644); err != nil { return "", err }
-
-	updateStats(int64(len(data)))
-	displayPath := "/" + filepath.ToSlash(filepath.Join(filepath.Base(OutputDir), saveRelPath))
-	fmt.Printf("           └── %s\n", displayPath)
-
-	processedFiles[targetURL] = saveRelPath
-	return saveRelPath, nil
-}
-
-// processCSSContent: CSS 파일 내부의 url()을 찾아 리소스를 다운로드합니다.
-func processCSSContent(ctx context.Context, cssData []byte, contextURL string, cssSavedDir string) []byte {
-	if ctx.Err() != nil { return cssData }
-
-	cssStr := string(cssData)
-	re := regexp.MustCompile(`url\(['"]?(.*?)['"]?\)`)
-	newCSS := re.ReplaceAllStringFunc(cssStr, func(match string) string {
-		if ctx.Err() != nil { return match }
-
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 2 { return match }
-		link := strings.TrimSpace(parts[1])
-		
-		if shouldIgnoreLink(link) { return match }
-
-		resourcePath, err := downloadResource(ctx, link, contextURL)
-		if err != nil { return match }
-
-		absCssDir := filepath.Join(OutputDir, cssSavedDir)
-		absResourcePath := filepath.Join(OutputDir, resourcePath)
-		relPath, err := filepath.Rel(absCssDir, absResourcePath)
-		if err != nil { return match }
-		return fmt.Sprintf("url('%s')", filepath.ToSlash(relPath))
-	})
-	return []byte(newCSS)
-}
-
-func isFontFile(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".woff", ".woff2", ".ttf", ".otf", ".eot": return true
-	}
-	return false
-}
-
-func updateStats(size int64) {
-	totalFiles++
-	totalBytes += size
-}
-
-func formatComma(n int64) string {
-	in := fmt.Sprintf("%d", n)
-	numOfDigits := len(in)
-	if n < 0 { numOfDigits-- }
-	numOfCommas := (numOfDigits - 1) / 3
-	out := make([]byte, len(in)+numOfCommas)
-	if n < 0 { in, out[0] = in[1:], '-' }
-	for i, j, k := len(in)-1, len(out)-1, 0; ; i, j = i-1, j-1 {
-		out[j] = in[i]
-		if i == 0 { return string(out) }
-		if k++; k == 3 { j, k = j-1, 0; out[j] = ',' }
-	}
-}
+/*
+===============================================================================================
+[프로그램 명세서 및 작동 논리 (Program Architecture & Logic Specification)]
+Designed for AI Context Understanding
+
+1. 프로그램 개요 (Overview)
+   - 이 프로그램은 정적 웹사이트(로컬 파일 시스템) 또는 원격 웹사이트(URL)의 모든 리소스를
+     재귀적으로 수집하여 로컬 환경에서 오프라인으로 열람 가능하도록 미러링(Mirroring)하는 도구입니다.
+   - 단순한 파일 다운로드가 아닌, HTML/CSS 내부의 참조 링크를 분석하여 로컬 상대 경로로 자동 변환합니다.
+
+2. 실행 모드 (Execution Modes)
+   A. 원격 모드 (Remote Mode)
+      - 조건: 입력값이 "http://" 또는 "https://"로 시작할 때.
+      - 동작: Headless Browser (Chromedp)를 사용하여 페이지를 엽니다.
+      - 특징:
+        1. 메인 스레드와 별개로 고루틴(Goroutine)이 브라우저 렌더링을 즉시 시작 (Pre-fetching).
+        2. 1920x1080 해상도로 렌더링하며, 초기 로딩 5초 대기 (-settle 로 변경 가능).
+        3. 렌더링된 최종 DOM(OuterHTML)을 추출하여 파싱.
+        4. Chrome 프로세스는 실행 동안 하나만 띄워 공유하며, 페이지마다 탭을 엽니다. (동시 탭 수: -tabs, 기본값 2)
+        5. 렌더링 중 브라우저가 받은 모든 응답(CDP Network)을 캡처하여 리소스 저장에 그대로 사용.
+           (같은 쿠키/User-Agent로 받은 내용이며, XHR·지연 로딩 이미지·JS가 요청한 폰트 등 HTML에 없는 리소스도 저장)
+           "-capture=false" 지정 시 기존처럼 모든 리소스를 HTTP로 다시 다운로드합니다.
+   B. 로컬 모드 (Local Mode)
+      - 조건: 입력값이 일반 파일/폴더 경로일 때.
+      - 동작: os.ReadFile을 통해 파일을 직접 읽습니다.
+      - 특징: <script> 태그 내부의 텍스트까지 분석하여 동적으로 연결된 .html 파일도 추적.
+   - 인라인 스크립트 분석 (두 모드 공통): JavaScript 렉서로 인라인 <script>(JavaScript, 모듈, JSON)의 문자열·템플릿 리터럴을 읽어
+     (주석·정규식 안의 값은 제외) 같은 사이트의 .html 은 페이지로 처리하고 (로컬: 즉시, 원격: -depth 범위 안에서 추적),
+     json·이미지·wasm·css·폰트·미디어 등 리소스는 다운로드한 뒤 로컬 경로로 바꿉니다. ("img/" + name 처럼 조합한 경로는 제외)
+
+3. 실행 옵션 (Flags)
+   - "-o [경로]": 지정된 경로에 결과물을 저장합니다. (예: -o my_site)
+   - "-o ." 또는 옵션 미지정: 기본값 "front_local" 폴더에 저장합니다.
+   - "-j [N]": 동시에 다운로드할 리소스 수 (워커 풀 크기, 기본값 4).
+   - "-naming [path|hash]": 리소스 저장 파일명 전략.
+       path (기본값): assets/<호스트>/<원본 경로> 구조를 유지. 쿼리가 다르면(style.css?v=2) 파일명에 쿼리 해시 부착.
+       hash: assets/<이름>.<내용 해시>.<확장자> 로 평탄하게 저장. 같은 내용은 한 파일로 합쳐짐.
+   - "-format [folder|single|archive]": 출력 형식.
+       folder (기본값): 리소스를 assets/, fonts/ 폴더에 파일로 저장.
+       single: 페이지마다 하나의 .html로 저장. CSS는 <style>, JS는 <script> 안에 내장하고,
+               이미지·폰트(CSS 내부 url() 포함)는 data: URI로 변환합니다. (버그 리포트 첨부용)
+       archive: 미러 폴더를 만들지 않고 -archive 파일만 생성합니다.
+   - "-srcset [all|largest]": 반응형 이미지(img/source[srcset], data-srcset, link[imagesrcset]) 후보 다운로드 범위.
+       all (기본값): 모든 후보를 받아 설명자(480w, 2x)를 유지한 채 경로만 변환.
+       largest: 가장 큰 후보(w 우선, 없으면 x)만 받고 srcset을 그 후보 하나로 줄입니다.
+   - 미디어: video[src|poster], audio[src], video/audio 안의 source[src], track[src](자막), object[data], embed[src]도 수집합니다.
+   - "-max-media [크기]": 이 크기(예: 200MB, 1.5GB)를 넘는 미디어는 받지 않습니다. (기본값: 무제한)
+       "-oversize [skip|placeholder]": skip(기본값)은 원본 URL 유지, placeholder는 요소를 원본 링크가 담긴 안내 상자로 교체.
+   - 스크립트(ES 모듈): 저장하는 .js/.mjs 파일과 인라인 <script type="module">에서 import 구문, export ... from,
+       import("./chunk.js"), new URL("./a.wasm", import.meta.url), import.meta.resolve() 의 대상을 찾아 재귀적으로 받고
+       로컬 상대 경로(./...)로 바꿉니다. (single 형식에서는 data: URI로 내장)
+       베어 지정자(import "react")와 런타임에 이름을 조합하는 청크(webpack 청크 ID 맵 등)는 변환하지 않습니다.
+   - "-integrity [recompute|drop]": 로컬로 바꾼 script/link의 Subresource Integrity(integrity 속성) 처리.
+       recompute (기본값): 저장된 내용(CSS 경로 변환 후)으로 해시를 다시 계산. drop: 속성 제거 (file:// 로 열 때 권장).
+       로컬 리소스의 crossorigin 속성과 <meta http-equiv="Content-Security-Policy">는 항상 제거합니다.
+   - "-archive [파일]": 리소스 다운로드와 Chrome 렌더링 중 발생한 모든 HTTP 요청/응답을 WARC 1.1로 기록합니다.
+       확장자로 형식 결정: .warc (비압축), .warc.gz (레코드별 gzip), .wacz (WARC + CDXJ 인덱스 + pages.jsonl 패키지).
+       -format folder/single 과 함께 쓰면 미러 폴더와 아카이브를 모두 생성합니다.
+   - "-report [파일]": 실행 결과를 JSON으로 저장합니다. (CI에서 깨진 미러를 확인하는 용도, 실패·중단된 경우에도 저장)
+       발견된 리소스 참조마다 참조한 페이지·요소·속성, 해석된 URL, 저장 경로, HTTP 상태, 크기, 소요 시간,
+       결과(saved / skipped: 크기 제한으로 생략 / failed)와 실패 원인을 기록합니다. summary.failed 로 실패 수를 확인할 수 있습니다.
+   - "-depth [N]": <a href> 링크를 N단계까지 따라가 사이트 전체를 미러링합니다. (기본값 0: 추적 안 함)
+       추적된 페이지의 링크는 로컬 .html 상대 경로로 변환됩니다. (/about -> about.html, /docs/ -> docs/index.html)
+   - "-scope [host|prefix|hosts]": 링크 추적 범위. host(기본값): 같은 호스트, prefix: 시작 URL 경로 하위,
+       hosts: 시작 호스트 + "-allow-hosts a.com,b.com" 목록 (하위 도메인 포함). 범위 밖 페이지는 hosts/<호스트>/ 에 저장.
+   - 로그인이 필요한 사이트: HTTP 리소스 요청과 Chrome 렌더링이 같은 헤더·쿠키·인증 정보(같은 신원)를 사용합니다.
+       "-header \"이름: 값\"" (여러 번 지정 가능, User-Agent도 변경), "-cookie \"이름=값; 이름2=값2\"" (시작 URL의 호스트),
+       "-cookies [파일]" (Netscape 형식 cookies.txt: curl -c, 브라우저 확장 프로그램으로 내보낸 파일),
+       "-auth 사용자:비밀번호" (HTTP Basic 인증), "-bearer [토큰]" (Authorization: Bearer).
+       추가 헤더와 Authorization은 크롤링 범위의 호스트(시작 호스트, -scope hosts 목록)로 가는 요청에만 붙여 외부 CDN 등으로 새지 않게 합니다.
+       렌더링 중 브라우저가 받은 쿠키(로그인 세션 갱신 등)는 이후 HTTP 리소스 요청에도 사용됩니다.
+   - "-steps [파일]": 원격 페이지를 렌더링(-wait 조건 대기)한 뒤 HTML을 가져오기 전에 순서대로 수행할 조작 단계 (.yaml, .yml, .json).
+       폼 로그인, 쿠키 배너 닫기, 탭 클릭처럼 조작해야 내용이 나타나는 페이지를 매번 같은 방식으로 캡처합니다.
+       항목마다 조작 하나: navigate(URL, 상대 경로는 현재 페이지 기준, 비우면 현재 페이지로 다시 이동), click(선택자),
+       type(선택자 + text), wait(선택자 / "2s" 같은 시간 / js 식), eval(JavaScript), scroll(선택자 / top / bottom / 픽셀).
+       공통 키: timeout(기본값 10s), optional(true: 실패해도 경고 후 계속), once(true: 시작 페이지에서만 수행, 로그인용).
+       예) - click: ".cookie-accept"
+             optional: true
+           - type: "#search"
+             text: localizer
+           - wait: ".results"
+   - 출력 폴더가 이미 존재할 경우 (비어 있지 않은 폴더):
+       "-force": 묻지 않고 삭제 후 다시 생성 (입력 폴더나 현재 작업 폴더를 포함하는 경로는 삭제하지 않음).
+       "-keep": 기존 파일을 유지하고 병합. 이미 받은 리소스는 다시 받지 않고, 페이지는 다시 처리합니다.
+       "-resume": 중단된(Ctrl+C, 시간 초과, 강제 종료) 이전 실행을 이어서 진행. -keep 과 같이 병합하되
+                  이전 실행에서 저장을 마친 페이지는 건너뛰고 그 페이지의 링크만 다시 추적합니다.
+                  (완료된 페이지는 출력 폴더의 .localizer-journal.jsonl 에 기록되며, 작업이 끝까지 완료되면 삭제됩니다)
+       옵션이 없으면 터미널에서 처리 방식을 묻고 (기본값: 취소), 표준 입력이 터미널이 아니면(CI, 파이프) 묻지 않고 실패합니다.
+   - 미리보기 서버: "localizer serve [-addr 127.0.0.1:8080] [-spa] [-quiet] [폴더]"
+       미러 결과물(기본값 front_local)을 로컬 HTTP 서버로 제공합니다. file:// 에서 동작하지 않는
+       ES 모듈, fetch(JSON), 서비스 워커를 확인할 때 사용합니다. (모듈 스크립트·wasm 등의 MIME 타입 보정, 캐시 비활성화)
+       -spa: 없는 페이지 경로(/users/42) 요청에 index.html로 응답 (클라이언트 라우팅 사이트용). 포트 0은 빈 포트 자동 선택.
+   - 기록 프록시: "localizer record [-proxy-addr 127.0.0.1:8081] [-ca-dir 폴더] [미러링 옵션] <시작 URL>"
+       로그인 등 직접 조작해야 도달하는 페이지용. 브라우저의 HTTP/HTTPS 프록시를 지정한 주소로 설정하고 사이트를 둘러본 뒤
+       Ctrl+C로 종료하면, 방문한 HTML 페이지(시작 URL 기준 -scope 범위 안)와 받은 응답으로 미러를 작성합니다.
+       HTTPS는 로컬 CA(기본값: 사용자 설정 폴더/localizer/localizer-ca.pem, 프록시 주소/ca.pem 에서도 받을 수 있음)가
+       발급한 인증서로 가로채므로, 이 CA를 브라우저/OS에 신뢰할 수 있는 인증 기관으로 등록해야 합니다.
+       기록된 페이지 사이의 링크는 로컬 경로로 변환되며 (-depth 는 사용하지 않음), -archive 를 지정하면 모든 요청/응답을 WARC로도 남깁니다.
+       WebSocket 등 프로토콜 전환 요청은 지원하지 않습니다.
+
+4. 타임아웃 및 리소스 관리 (Safety & Constraints)
+   - Global Timeout: 전체 작업은 기본 60초로 제한됩니다. (-timeout, 0이면 무제한) 초과 시 작업 취소 및 경고 출력.
+   - HTTP Client: 개별 리소스 다운로드는 기본 30초 타임아웃이 적용됩니다. (-request-timeout)
+   - Render: 페이지별 브라우저 렌더링은 기본 30초로 제한되며 (-render-timeout), 로드 후 5초간 DOM 구성을 기다립니다. (-settle)
+   - 경고 메시지에는 어떤 시간 제한(전체 작업 / 리소스 요청 / 페이지 렌더링)을 초과했는지 표시됩니다.
+   - Wait Strategy (-wait): 고정 대기(sleep) 대신 렌더링 완료 조건을 지정할 수 있습니다. (쉼표로 여러 개, 순서대로 대기)
+       load / domcontentloaded: 해당 페이지 이벤트, networkidle: 요청이 -idle(기본 500ms) 동안 없음,
+       selector: -wait-selector 요소 등장, js: -wait-js 표현식이 참. 조건이 -wait-max 안에 충족되지 않으면 현재 상태로 캡처.
+   - Auto Scroll (-scroll): 렌더링 대기(-wait)와 조작 단계(-steps) 후 페이지를 화면 높이씩 바닥까지 스크롤하며,
+       스크롤할 때마다 새 네트워크 요청이 끝나기를(-idle 동안 요청 없음, 최대 2초) 기다립니다. loading="lazy" 이미지와
+       무한 스크롤 피드가 불러온 내용까지 캡처하며, 바닥에서 페이지가 더 길어지지 않거나 -scroll-max-height(기본 50000px) /
+       -scroll-max-time(기본 15초, 0: 렌더링 제한까지)에 도달하면 맨 위로 돌아가 현재 상태로 캡처합니다.
+   - Caching: 이미 다운로드된 리소스(Disk Cache)는 중복 요청하지 않고 건너뜁니다.
+   - Concurrency: 리소스 다운로드는 워커 풀(-j)로 병렬 처리되며, 동일 URL은 동시에 두 번 요청되지 않습니다.
+
+5. 데이터 처리 파이프라인 (Processing Pipeline)
+   Step 1. 입력값 분석 (URL vs Local) 및 모드 설정.
+   Step 2. 사전 유효성 검사 (URL 접속 가능 여부 / 파일 존재 여부).
+   Step 3. 출력 디렉토리 준비 (/assets, /fonts 생성).
+   Step 4. HTML 파싱 (Golang net/html 패키지 사용).
+           <base href>가 있으면 모든 상대 링크를 그 주소 기준으로 해석하고, 저장 시 <base>는 제거(target만 유지).
+           로컬 모드의 '/'로 시작하는 경로(/css/site.css)는 입력 폴더를 사이트 루트로 보고 해석.
+   Step 5. DOM 순회 -> 리소스 발견 -> 다운로드 -> 경로 재계산(filepath.Rel) -> 속성값 수정.
+   Step 6. CSS 파일인 경우, CSS 토크나이저로 url(...), @import "...", image-set(...) 참조를 찾아 재귀적으로 리소스 다운로드.
+           (주석·이스케이프·괄호가 포함된 URL을 올바르게 처리하며, 참조 외의 내용은 원문 그대로 유지)
+           HTML 안의 <style> 블록과 style 속성도 같은 방식으로 처리하며, 상대 경로는 HTML 파일 위치 기준으로 계산.
+   Step 7. 최종 파일 저장 및 통계 출력.
+
+6. 패키지 구조 (Package Layout)
+   - localizer/ : 미러링 엔진. Options로 Mirror를 생성하고 Run(ctx)으로 실행하며, 결과 요약(Result)을 반환합니다.
+                  모든 상태가 Mirror 인스턴스에 있으므로 한 프로세스에서 여러 미러를 독립적으로 실행할 수 있습니다.
+                  NewPreview(PreviewOptions)로 결과물 미리보기 서버를, Mirror.NewRecorder(RecordOptions)로 기록 프록시를 만들 수 있습니다.
+   - main.go    : 인자 파싱, 사용자 확인, 결과 출력만 담당하는 얇은 CLI 래퍼.
+
+===============================================================================================
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"localizer/localizer"
+)
+
+// ==========================================
+// [메인 실행 함수]
+// ==========================================
+func main() {
+	// 0. 프로그램 배너 출력
+	fmt.Println("===================================================")
+	fmt.Println("   JunghoKor's AI Web page local downloader v0.2")
+	fmt.Println("===================================================")
+
+	// 하위 명령: serve (미러 결과물 미리보기 서버)
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[1:])
+		return
+	}
+
+	// 하위 명령: record (기록 프록시). 미러링 옵션을 그대로 사용하므로 명령 이름만 제거하고 이어서 처리
+	recordMode := len(os.Args) > 1 && os.Args[1] == "record"
+	if recordMode { os.Args = append(os.Args[:1], os.Args[2:]...) }
+
+	// 1. 옵션 정의
+	outputFlag := flag.String("o", "", "결과물이 저장될 폴더 경로")
+	workersFlag := flag.Int("j", localizer.DefaultWorkers, "동시에 다운로드할 리소스 수 (워커 풀 크기)")
+	namingFlag := flag.String("naming", string(localizer.NamingPath), "리소스 저장 파일명 전략 (path: 원본 호스트/경로 유지, hash: 내용 해시 접미사)")
+	formatFlag := flag.String("format", string(localizer.FormatFolder), "출력 형식 (folder: assets/fonts 폴더에 저장, single: 페이지마다 리소스를 내장한 .html 하나, archive: -archive 파일만 생성)")
+	srcsetFlag := flag.String("srcset", string(localizer.SrcsetAll), "srcset 후보 이미지 다운로드 범위 (all: 모든 후보, largest: 가장 큰 후보만)")
+	maxMediaFlag := flag.String("max-media", "", "이 크기를 넘는 미디어(video, audio, object, embed)는 받지 않음 (예: 200MB, 기본값: 무제한)")
+	oversizeFlag := flag.String("oversize", string(localizer.OversizeSkip), "크기 제한을 넘는 미디어 처리 (skip: 원본 URL 유지, placeholder: 안내 상자로 교체)")
+	integrityFlag := flag.String("integrity", string(localizer.IntegrityRecompute), "로컬 리소스의 integrity 속성 처리 (recompute: 저장된 내용으로 다시 계산, drop: 제거)")
+	reportFlag := flag.String("report", "", "페이지/리소스 참조별 처리 결과를 저장할 JSON 파일")
+	archiveFlag := flag.String("archive", "", "모든 HTTP 요청/응답을 기록할 아카이브 파일 (.warc, .warc.gz, .wacz)")
+	depthFlag := flag.Int("depth", 0, "따라갈 <a href> 링크의 최대 깊이 (0: 링크를 따라가지 않음)")
+	scopeFlag := flag.String("scope", string(localizer.ScopeHost), "링크 추적 범위 (host: 같은 호스트, prefix: 시작 경로 하위, hosts: -allow-hosts 목록 포함)")
+	allowHostsFlag := flag.String("allow-hosts", "", "-scope hosts 에서 추가로 허용할 호스트 목록 (쉼표 구분)")
+	timeoutFlag := flag.Duration("timeout", localizer.DefaultTimeouts.Total, "전체 작업 시간 제한 (0: 무제한)")
+	requestTimeoutFlag := flag.Duration("request-timeout", localizer.DefaultTimeouts.Request, "개별 리소스 요청 시간 제한 (0: 무제한)")
+	renderTimeoutFlag := flag.Duration("render-timeout", localizer.DefaultTimeouts.Render, "페이지별 브라우저 렌더링 시간 제한 (0: 무제한)")
+	settleFlag := flag.Duration("settle", localizer.DefaultTimeouts.Settle, "페이지 로드 후 DOM 구성 대기 시간 (0: 대기 안 함)")
+	waitFlag := flag.String("wait", "", "렌더링 완료 조건 (쉼표 구분: load, domcontentloaded, networkidle, selector, js, sleep)")
+	waitSelectorFlag := flag.String("wait-selector", "", "나타날 때까지 기다릴 CSS 선택자 (지정 시 selector 조건 사용)")
+	waitJSFlag := flag.String("wait-js", "", "참이 될 때까지 기다릴 JavaScript 표현식 (지정 시 js 조건 사용)")
+	idleFlag := flag.Duration("idle", localizer.DefaultIdle, "networkidle: 네트워크 요청이 없어야 하는 시간")
+	waitMaxFlag := flag.Duration("wait-max", 0, "조건별 최대 대기 시간 (0: 렌더링 제한까지)")
+	scrollFlag := flag.Bool("scroll", false, "캡처 전에 페이지를 바닥까지 조금씩 스크롤 (지연 로딩 이미지, 무한 스크롤)")
+	scrollMaxHeightFlag := flag.Int("scroll-max-height", localizer.DefaultScroll.MaxHeight, "자동 스크롤 최대 위치 (픽셀, 0: 무제한)")
+	scrollMaxTimeFlag := flag.Duration("scroll-max-time", localizer.DefaultScroll.MaxTime, "페이지별 자동 스크롤 시간 제한 (0: 렌더링 제한까지)")
+	tabsFlag := flag.Int("tabs", localizer.DefaultMaxTabs, "동시에 처리할 페이지 수 (공유 브라우저에 동시에 열리는 탭 수)")
+	captureFlag := flag.Bool("capture", true, "원격 모드에서 브라우저가 받은 응답을 그대로 저장 (false: 모든 리소스를 HTTP로 다시 다운로드)")
+	forceFlag := flag.Bool("force", false, "출력 폴더가 이미 있으면 묻지 않고 삭제 후 다시 생성")
+	keepFlag := flag.Bool("keep", false, "출력 폴더가 이미 있으면 유지하고 병합 (이미 받은 리소스는 다시 받지 않음)")
+	resumeFlag := flag.Bool("resume", false, "중단된 이전 실행을 이어서 진행 (완료된 페이지는 건너뜀)")
+	proxyAddrFlag := flag.String("proxy-addr", localizer.DefaultRecordAddr, "record: 기록 프록시 수신 주소")
+	caDirFlag := flag.String("ca-dir", "", "record: HTTPS 기록용 로컬 CA 인증서 보관 폴더 (기본값: 사용자 설정 폴더/localizer)")
+	var headerFlags, cookieFlags listFlag
+	flag.Var(&headerFlags, "header", "추가 요청 헤더 \"이름: 값\" (여러 번 지정 가능, User-Agent 변경 가능)")
+	flag.Var(&cookieFlags, "cookie", "요청에 보낼 쿠키 \"이름=값; 이름2=값2\" (시작 URL의 호스트, 여러 번 지정 가능)")
+	cookiesFileFlag := flag.String("cookies", "", "Netscape 형식 cookies.txt 파일에서 쿠키 가져오기")
+	authFlag := flag.String("auth", "", "HTTP Basic 인증 정보 (사용자:비밀번호)")
+	bearerFlag := flag.String("bearer", "", "Authorization: Bearer 토큰")
+	stepsFlag := flag.String("steps", "", "렌더링 후 캡처 전에 수행할 조작 단계 파일 (.yaml, .yml, .json)")
+
+	// 2. [전처리] 인자 재배열 후 옵션 파싱
+	os.Args = reorderArgs(flag.CommandLine, os.Args)
+	flag.Parse()
+
+	// 3. 출력 폴더 결정 로직
+	outputDir := *outputFlag
+	if outputDir == "" || outputDir == "." {
+		outputDir = "front_local"
+	}
+
+	// 4. 입력값 분석 및 모드 결정
+	args := flag.Args()
+	inputArg := "front" // 기본값
+	if len(args) > 0 {
+		inputArg = args[0]
+	}
+
+	waitModes, err := localizer.ParseWaitModes(*waitFlag)
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+	// -wait 없이 선택자/표현식만 지정한 경우 해당 조건을 사용
+	if len(waitModes) == 0 && *waitSelectorFlag != "" { waitModes = append(waitModes, localizer.WaitSelector) }
+	if len(waitModes) == 0 && *waitJSFlag != "" { waitModes = append(waitModes, localizer.WaitJS) }
+
+	maxMedia, err := localizer.ParseSize(*maxMediaFlag)
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+
+	headers, cookies, err := requestIdentity(headerFlags, cookieFlags, *cookiesFileFlag)
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+
+	var steps []localizer.Step
+	if *stepsFlag != "" {
+		if steps, err = localizer.LoadSteps(*stepsFlag); err != nil {
+			fmt.Printf("❌ 오류: 단계 파일을 읽을 수 없습니다: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// 출력 폴더 처리 방식 결정 (아카이브 전용 모드는 폴더를 만들지 않으므로 확인하지 않음)
+	output, err := outputPolicy(*forceFlag, *keepFlag, *resumeFlag)
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+	if output == "" && localizer.Format(*formatFlag) != localizer.FormatArchive {
+		var ok bool
+		if output, ok = confirmOutput(outputDir); !ok { os.Exit(1) }
+	}
+
+	mirror, err := localizer.New(localizer.Options{
+		Source:    inputArg,
+		OutputDir: outputDir,
+		Output:    output,
+		Log:       os.Stdout,
+		Workers:   *workersFlag,
+		Naming:    localizer.Naming(*namingFlag),
+		Format:    localizer.Format(*formatFlag),
+		Srcset:    localizer.SrcsetMode(*srcsetFlag),
+		Archive:   *archiveFlag,
+		Report:    *reportFlag,
+
+		Depth:        *depthFlag,
+		Scope:        localizer.Scope(*scopeFlag),
+		AllowedHosts: splitList(*allowHostsFlag),
+
+		Timeouts: localizer.Timeouts{
+			Total:   noLimit(*timeoutFlag),
+			Request: noLimit(*requestTimeoutFlag),
+			Render:  noLimit(*renderTimeoutFlag),
+			Settle:  noLimit(*settleFlag),
+		},
+		Wait: localizer.Wait{
+			Modes:    waitModes,
+			Selector: *waitSelectorFlag,
+			JS:       *waitJSFlag,
+			Idle:     *idleFlag,
+			Max:      *waitMaxFlag,
+		},
+		Scroll: localizer.Scroll{
+			Enabled:   *scrollFlag,
+			MaxHeight: noLimitInt(*scrollMaxHeightFlag),
+			MaxTime:   noLimit(*scrollMaxTimeFlag),
+		},
+		MaxMediaSize:  maxMedia,
+		OversizeMedia: localizer.OversizePolicy(*oversizeFlag),
+		Integrity:     localizer.IntegrityMode(*integrityFlag),
+
+		MaxTabs:        *tabsFlag,
+		DisableCapture: !*captureFlag,
+
+		Headers:     headers,
+		Cookies:     cookies,
+		BasicAuth:   *authFlag,
+		BearerToken: *bearerFlag,
+
+		Steps: steps,
+	})
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+
+	if recordMode {
+		runRecord(mirror, localizer.RecordOptions{Addr: *proxyAddrFlag, CADir: *caDirFlag})
+		return
+	}
+
+	// 전체 작업 시간 제한(-timeout)은 Mirror가 Prefetch/Run 시점부터 적용
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 원격 모드일 경우, 메인 페이지 렌더링을 백그라운드에서 즉시 시작
+	// (Run이 호출되지 않고 끝나는 경우를 위해 브라우저 종료를 예약)
+	mirror.Prefetch(ctx)
+	defer mirror.Close()
+
+	// 5. 입력 경로 유효성 검사 (실제 접속/존재 확인)
+	if err := mirror.Validate(); err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		mirror.Close()
+		os.Exit(1)
+	}
+
+	// 6. 작업 시작
+	printStartInfo(mirror)
+	result, err := mirror.Run(ctx)
+
+	// 7. 결과 통계 출력
+	printResult(result, err)
+}
+
+// ==========================================
+// [serve 하위 명령]
+// ==========================================
+
+// runServe: 미러 결과물 폴더를 로컬 HTTP 서버로 제공합니다. (localizer serve [-addr 주소] [-spa] [폴더])
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addrFlag := fs.String("addr", localizer.DefaultPreviewAddr, "수신 주소 (포트 0: 빈 포트 자동 선택)")
+	spaFlag := fs.Bool("spa", false, "없는 페이지 경로 요청에 index.html 제공 (클라이언트 라우팅 사이트용)")
+	quietFlag := fs.Bool("quiet", false, "요청 로그를 출력하지 않음")
+	fs.Parse(reorderArgs(fs, args)[1:])
+
+	dir := "front_local" // 미러링 기본 출력 폴더
+	if fs.NArg() > 0 { dir = fs.Arg(0) }
+
+	var logOut io.Writer = os.Stdout
+	if *quietFlag { logOut = nil }
+	preview, err := localizer.NewPreview(localizer.PreviewOptions{Dir: dir, Addr: *addrFlag, SPA: *spaFlag, Log: logOut})
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	absDir, _ := filepath.Abs(preview.Dir())
+	fmt.Printf("🌐 미리보기 서버 실행 중\n   🔗 주소: %s\n   📂 폴더: %s\n", preview.URL(), absDir)
+	if *spaFlag { fmt.Println("   🔀 SPA 모드: 없는 페이지 경로는 index.html로 응답") }
+	fmt.Println("   ⏹️  종료: Ctrl+C")
+	fmt.Println("==================================================")
+
+	if err := preview.Run(ctx); err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("\n👋 미리보기 서버를 종료했습니다.")
+}
+
+// ==========================================
+// [record 하위 명령]
+// ==========================================
+
+// runRecord: 기록 프록시를 실행하고, Ctrl+C로 종료하면 방문한 페이지로 미러를 작성합니다.
+// (localizer record [-proxy-addr 주소] [-ca-dir 폴더] [미러링 옵션] <시작 URL>)
+func runRecord(mirror *localizer.Mirror, opts localizer.RecordOptions) {
+	defer mirror.Close()
+	recorder, err := mirror.NewRecorder(opts)
+	if err != nil {
+		fmt.Printf("❌ 오류: %v\n", err)
+		return
+	}
+
+	// 첫 Ctrl+C는 기록을 멈추고 미러를 작성, 이후의 Ctrl+C는 기본 동작(즉시 종료)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	absOut, _ := filepath.Abs(mirror.OutputDir())
+	fmt.Printf("⏺️  기록 프록시 실행 중\n   🔗 프록시: %s (브라우저의 HTTP/HTTPS 프록시로 설정)\n", recorder.ProxyURL())
+	fmt.Printf("   🔐 CA 인증서: %s\n      (HTTPS 사이트 기록 시 신뢰할 수 있는 인증 기관으로 등록, %s/ca.pem 에서도 받을 수 있음)\n", recorder.CACertPath(), recorder.ProxyURL())
+	fmt.Printf("   🌐 기준 URL: %s (이 범위 안에서 방문한 페이지를 미러링)\n   📂 출력: %s\n", mirror.RootDir(), absOut)
+	fmt.Println("   ⏹️  기록 종료 및 미러 작성: Ctrl+C")
+	fmt.Println("==================================================")
+
+	result, err := recorder.Run(ctx)
+	printResult(result, err)
+}
+
+// ==========================================
+// [CLI 보조 함수들]
+// ==========================================
+
+// reorderArgs: Go flag 패키지는 [옵션] [인자] 순서를 강제하므로, 사용자가 섞어 써도 동작하도록 재배열합니다.
+// 값을 받는 옵션(-o, -j 등)은 flag 정의를 조회하여 다음 인자를 함께 옮깁니다. (args[0]은 프로그램/하위 명령 이름)
+func reorderArgs(fs *flag.FlagSet, args []string) []string {
+	var flagArgs []string
+	var normalArgs []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			normalArgs = append(normalArgs, arg)
+			continue
+		}
+		// -name=value 는 이름만으로 flag 정의를 조회 (-oversize=placeholder 가 -o versize=... 로 나뉘지 않도록)
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		// -otest 처럼 붙여쓴 경우 분리
+		if fs.Lookup("o") != nil && strings.HasPrefix(arg, "-o") && len(arg) > 2 && arg[2] != '=' && fs.Lookup(name) == nil {
+			flagArgs = append(flagArgs, "-o", arg[2:])
+			continue
+		}
+		flagArgs = append(flagArgs, arg)
+		if hasValue { continue }
+		// 값을 받는 옵션 뒤에 값이 바로 오면 같이 가져감
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			flagArgs = append(flagArgs, args[i+1])
+			i++
+		}
+	}
+	return append([]string{args[0]}, append(flagArgs, normalArgs...)...)
+}
+
+// isBoolFlag: 값 없이 사용하는 불리언 옵션인지 확인합니다.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// noLimit: CLI에서 0으로 지정한 시간 제한을 "제한 없음"(음수)으로 변환합니다.
+func noLimit(d time.Duration) time.Duration {
+	if d == 0 { return -1 }
+	return d
+}
+
+// noLimitInt: CLI에서 0으로 지정한 정수 제한을 "제한 없음"(음수)으로 변환합니다.
+func noLimitInt(n int) int {
+	if n == 0 { return -1 }
+	return n
+}
+
+// splitList: 쉼표로 구분된 목록을 나눕니다. (빈 항목 제외)
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" { list = append(list, item) }
+	}
+	return list
+}
+
+// listFlag: 여러 번 지정할 수 있는 문자열 옵션 (-header, -cookie)
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ", ") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// requestIdentity: -header, -cookie, -cookies 옵션을 요청 헤더와 쿠키 목록으로 변환합니다.
+func requestIdentity(headerArgs []string, cookieArgs []string, cookiesFile string) (http.Header, []*http.Cookie, error) {
+	headers := make(http.Header)
+	for _, arg := range headerArgs {
+		name, value, err := localizer.ParseHeader(arg)
+		if err != nil { return nil, nil, err }
+		headers.Add(name, value)
+	}
+
+	var cookies []*http.Cookie
+	if cookiesFile != "" {
+		loaded, err := localizer.LoadCookiesFile(cookiesFile)
+		if err != nil { return nil, nil, fmt.Errorf("쿠키 파일을 읽을 수 없습니다: %w", err) }
+		cookies = append(cookies, loaded...)
+	}
+	for _, arg := range cookieArgs {
+		parsed, err := localizer.ParseCookies(arg)
+		if err != nil { return nil, nil, err }
+		cookies = append(cookies, parsed...)
+	}
+	return headers, cookies, nil
+}
+
+// outputPolicy: -force, -keep, -resume 옵션으로 출력 폴더 처리 방식을 정합니다. (지정하지 않으면 빈 값)
+func outputPolicy(force bool, keep bool, resume bool) (localizer.OutputPolicy, error) {
+	var policy localizer.OutputPolicy
+	count := 0
+	if force { policy, count = localizer.OutputOverwrite, count+1 }
+	if keep { policy, count = localizer.OutputMerge, count+1 }
+	if resume { policy, count = localizer.OutputResume, count+1 }
+	if count > 1 { return "", fmt.Errorf("-force, -keep, -resume 는 함께 사용할 수 없습니다") }
+	return policy, nil
+}
+
+// confirmOutput: 출력 폴더가 존재하면 처리 방식을 묻습니다. (기본값: 취소)
+// 표준 입력이 터미널이 아니면(CI, 파이프) 묻지 않고 실패하며, 폴더가 없으면 OutputFail을 반환합니다.
+func confirmOutput(outputDir string) (localizer.OutputPolicy, bool) {
+	if !localizer.OutputExists(outputDir) { return localizer.OutputFail, true }
+
+	absPath, _ := filepath.Abs(outputDir)
+	fmt.Printf("\n⚠️  경고: 출력 폴더가 이미 존재합니다.\n   경로: %s\n", absPath)
+	if !isTerminal(os.Stdin) {
+		fmt.Println("❌ 입력을 받을 수 없어 작업을 취소합니다. -force(삭제 후 생성), -keep(병합), -resume(이어서 진행) 중 하나를 지정하세요.")
+		return "", false
+	}
+	fmt.Print("   [d] 삭제 후 다시 생성 / [k] 유지하고 병합 / [r] 이어서 진행 / [N] 취소: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	switch strings.TrimSpace(strings.ToLower(input)) {
+	case "d", "y":
+		return localizer.OutputOverwrite, true
+	case "k":
+		return localizer.OutputMerge, true
+	case "r":
+		return localizer.OutputResume, true
+	}
+	fmt.Println("❌ 작업을 취소합니다.")
+	return "", false
+}
+
+// isTerminal: 파일이 터미널(문자 장치)인지 확인합니다. (문자 장치인 /dev/null 은 제외)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 { return false }
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+func printStartInfo(m *localizer.Mirror) {
+	mode := "로컬 파일 분석"
+	if m.IsRemote() {
+		mode = "웹 렌더링 (Headless Browser)"
+	}
+	absOut, _ := filepath.Abs(m.OutputDir())
+	fmt.Printf("🚀 작업 시작 (%s)\n   🔗 소스: %s (시작: %s)\n   📂 출력: %s\n", mode, m.RootDir(), m.StartFile(), absOut)
+	fmt.Println("==================================================")
+}
+
+func printResult(result *localizer.Result, err error) {
+	fmt.Println("==================================================")
+	if err != nil {
+		// Context 타임아웃 에러인지 확인 (어떤 시간 제한을 넘었는지 표시)
+		var te *localizer.TimeoutError
+		if errors.As(err, &te) {
+			fmt.Printf("*** Warning : Timeout - %v\n", te)
+		} else if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "context deadline exceeded") {
+			fmt.Printf("*** Warning : Timeout\n")
+		} else {
+			fmt.Printf("❌ 오류 발생: %v\n", err)
+		}
+	} else {
+		fmt.Printf("✅ 작업 완료!\n")
+	}
+	fmt.Printf("Total %d files, saved %s bytes\n", result.Files, formatComma(result.Bytes))
+	if result.Failed > 0 { fmt.Printf("⚠️  받지 못한 리소스 참조 %d개 (원본 URL 유지)\n", result.Failed) }
+}
+
+func formatComma(n int64) string {
+	in := fmt.Sprintf("%d", n)
+	numOfDigits := len(in)
+	if n < 0 { numOfDigits-- }
+	numOfCommas := (numOfDigits - 1) / 3
+	out := make([]byte, len(in)+numOfCommas)
+	if n < 0 { in, out[0] = in[1:], '-' }
+	for i, j, k := len(in)-1, len(out)-1, 0; ; i, j = i-1, j-1 {
+		out[j] = in[i]
+		if i == 0 { return string(out) }
+		if k++; k == 3 { j, k = j-1, 0; out[j] = ',' }
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestReorderArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("o", "", "")
+	fs.String("oversize", "", "")
+	fs.Int("j", 0, "")
+	fs.Bool("force", false, "")
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"prog", "-oDIR", "site"}, []string{"prog", "-o", "DIR", "site"}},
+		{[]string{"prog", "-o=DIR", "site"}, []string{"prog", "-o=DIR", "site"}},
+		{[]string{"prog", "site", "-o", "DIR"}, []string{"prog", "-o", "DIR", "site"}},
+		{[]string{"prog", "-oversize=placeholder", "site"}, []string{"prog", "-oversize=placeholder", "site"}},
+		{[]string{"prog", "--oversize=placeholder", "site"}, []string{"prog", "--oversize=placeholder", "site"}},
+		{[]string{"prog", "site", "-oversize", "placeholder"}, []string{"prog", "-oversize", "placeholder", "site"}},
+		{[]string{"prog", "-force", "site", "-j", "4"}, []string{"prog", "-force", "-j", "4", "site"}},
+		{[]string{"prog", "-force=false", "site"}, []string{"prog", "-force=false", "site"}},
+	}
+	for _, tt := range tests {
+		if got := reorderArgs(fs, tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("reorderArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}